@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -12,15 +13,23 @@ import (
 	"github.com/sater-151/todo-list/internal/app"
 	"github.com/sater-151/todo-list/internal/configuration"
 	"github.com/sater-151/todo-list/internal/credentials"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/tracing"
 )
 
 const version = "v1.0.0"
 
 func main() {
+	configPath := flag.String("config", configuration.DefaultPath,
+		"path to configuration.yaml; empty to use only TODO_* environment variables")
+	credentialsPath := flag.String("credentials", credentials.DefaultPath,
+		"path to credentials.yaml; empty to use only TODO_* environment variables")
+	flag.Parse()
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	config, err := configuration.NewConfig()
+	config, err := configuration.NewConfig(*configPath)
 	if err != nil {
 		slog.Error("NewConfig initialization failed.", slog.String("error", err.Error()))
 
@@ -28,18 +37,22 @@ func main() {
 	}
 	config.Version = version
 
-	logger := slog.New(zeroslog.New(
+	// Уровень фильтруется снаружи zeroslog, чтобы его можно было менять без перезапуска.
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(config.Logger.Level)
+
+	logger := slog.New(tracing.NewLogHandler(logctx.NewLevelHandler(zeroslog.New(
 		zeroslog.WithTimeFormat("2006-01-02 15:04:05.000 -07:00"),
 		zeroslog.WithOutput(os.Stderr),
 		zeroslog.WithColors(),
-		zeroslog.WithMinLevel(config.Logger.Level),
-	))
+		zeroslog.WithMinLevel(slog.LevelDebug),
+	), logLevel)))
 
 	slog.SetDefault(logger)
 
 	logger.Info("starting application...", "version", version)
 
-	creds, err := credentials.NewCredentials()
+	creds, err := credentials.NewCredentials(*credentialsPath)
 	if err != nil {
 		logger.Error("newCredentials initialization failed", slog.String("error", err.Error()))
 
@@ -49,6 +62,8 @@ func main() {
 	application, err := app.New(ctx, &app.Dependencies{
 		Configuration: config,
 		Credentials:   creds,
+		ConfigPath:    *configPath,
+		LogLevel:      logLevel,
 	})
 	if err != nil {
 		logger.Error("application initialization failed", slog.String("error", err.Error()))
@@ -71,5 +86,12 @@ func main() {
 	logger.Info("please wait, services are stopping...", "version", version)
 	wg.Wait()
 
+	closeCtx, cancel := context.WithTimeout(context.Background(), config.HTTPServer.ShutdownTimeout)
+	defer cancel()
+
+	if err := application.Close(closeCtx); err != nil {
+		logger.Error("application close failed", slog.String("error", err.Error()))
+	}
+
 	logger.Info("application is stopped correctly. The force will be with you")
 }
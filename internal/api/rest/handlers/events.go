@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/eventbus"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+const eventsHeartbeat = 15 * time.Second
+
+type (
+	IEventSubscriber interface {
+		Subscribe(lastEventID string) (*eventbus.Subscription, []models.TaskEvent, bool)
+	}
+)
+
+type EventServerDependencies struct {
+	Events IEventSubscriber `validate:"required"`
+}
+
+type EventServer struct {
+	events IEventSubscriber
+}
+
+func NewEventHandlers(d *EventServerDependencies) (*EventServer, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("rest.NewEventHandlers", d, err)
+	}
+
+	return &EventServer{
+		events: d.Events,
+	}, nil
+}
+
+// Events отдаёт поток Server-Sent Events с изменениями задач. По заголовку
+// Last-Event-ID (или параметру last_event_id) досылаются пропущенные события;
+// если их уже нет в истории, первым приходит событие reset.
+func (s *EventServer) Events(res http.ResponseWriter, req *http.Request) {
+	rc := http.NewResponseController(res)
+
+	// Поток живёт дольше WriteTimeout сервера.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	lastEventID := req.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = req.FormValue("last_event_id")
+	}
+
+	sub, backlog, complete := s.events.Subscribe(lastEventID)
+	defer sub.Close()
+
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	if !complete {
+		backlog = append([]models.TaskEvent{{Type: models.TaskEventReset, Time: time.Now().UTC()}}, backlog...)
+	}
+
+	for i := range backlog {
+		if err := writeEvent(req.Context(), res, &backlog[i]); err != nil {
+			return
+		}
+	}
+
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+
+			if err := writeEvent(req.Context(), res, &event); err != nil {
+				return
+			}
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(ctx context.Context, res http.ResponseWriter, event *models.TaskEvent) error {
+	data, err := sonic.Marshal(event)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return err
+	}
+
+	if event.ID != "" {
+		if _, err := fmt.Fprintf(res, "id: %s\n", event.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data)
+
+	return err
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+type (
+	IFeedUsecase interface {
+		Token(ctx context.Context) (string, error)
+		RegenerateToken(ctx context.Context) (string, error)
+	}
+)
+
+type FeedServerDependencies struct {
+	FeedUsecase IFeedUsecase `validate:"required"`
+}
+
+type FeedServer struct {
+	feedUsecase IFeedUsecase
+}
+
+func NewFeedHandlers(d *FeedServerDependencies) (*FeedServer, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("rest.NewFeedHandlers", d, err)
+	}
+
+	return &FeedServer{
+		feedUsecase: d.FeedUsecase,
+	}, nil
+}
+
+func (s *FeedServer) GetFeedToken(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	token, err := s.feedUsecase.Token(req.Context())
+	if err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	writeFeedToken(res, req, token)
+}
+
+// PostFeedToken выдаёт новый секрет фида и отзывает прежние ссылки на календарь.
+func (s *FeedServer) PostFeedToken(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	token, err := s.feedUsecase.RegenerateToken(req.Context())
+	if err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	writeFeedToken(res, req, token)
+}
+
+func writeFeedToken(res http.ResponseWriter, req *http.Request, token string) {
+	res.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(res).Encode(models.JWTToken{Token: token}); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
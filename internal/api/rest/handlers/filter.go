@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/sater-151/todo-list/internal/models"
+)
+
+func parseTaskFilter(req *http.Request) (*models.TaskFilter, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, fmt.Errorf("parsing query: %w", err)
+	}
+
+	filter := &models.TaskFilter{
+		Search: req.FormValue("search"),
+		Period: req.FormValue("period"),
+		From:   req.FormValue("from"),
+		To:     req.FormValue("to"),
+		Sort:   req.FormValue("sort"),
+		Order:  req.FormValue("order"),
+		Tags:   req.Form["tag"],
+	}
+
+	overdue, _, err := boolParam(req, "overdue")
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Overdue = overdue
+
+	repeating, ok, err := boolParam(req, "repeating")
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		filter.Repeating = &repeating
+	}
+
+	hasComment, ok, err := boolParam(req, "has_comment")
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		filter.HasComment = &hasComment
+	}
+
+	return filter, nil
+}
+
+// boolParam возвращает значение булева параметра запроса и признак того, что он задан.
+func boolParam(req *http.Request, name string) (bool, bool, error) {
+	value := req.FormValue(name)
+	if value == "" {
+		return false, false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, false, fmt.Errorf("query parameter [%s]: %w", name, err)
+	}
+
+	return b, true, nil
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bytedance/sonic"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+type (
+	IHealthUsecase interface {
+		Ready(ctx context.Context) (*models.Readiness, bool)
+	}
+)
+
+type HealthServerDependencies struct {
+	HealthUsecase IHealthUsecase `validate:"required"`
+}
+
+type HealthServer struct {
+	healthUsecase IHealthUsecase
+}
+
+func NewHealthHandlers(d *HealthServerDependencies) (*HealthServer, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("rest.NewHealthHandlers", d, err)
+	}
+
+	return &HealthServer{
+		healthUsecase: d.HealthUsecase,
+	}, nil
+}
+
+// Healthz сообщает, что процесс жив; зависимости не проверяются.
+func (s *HealthServer) Healthz(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(models.HealthCheck{Status: models.HealthOK}); err != nil {
+		logctx.Error(req.Context(), err.Error())
+	}
+}
+
+// Readyz возвращает результаты проверок готовности и 503, если сервис не готов.
+func (s *HealthServer) Readyz(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+	res.Header().Set("Cache-Control", "no-store")
+
+	readiness, ready := s.healthUsecase.Ready(req.Context())
+
+	if ready {
+		res.WriteHeader(http.StatusOK)
+	} else {
+		res.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(readiness); err != nil {
+		logctx.Error(req.Context(), err.Error())
+	}
+}
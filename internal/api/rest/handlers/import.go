@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/bytedance/sonic"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/utils/backup"
+	"github.com/sater-151/todo-list/internal/utils/ical"
+	"github.com/sater-151/todo-list/internal/utils/todotxt"
+)
+
+const maxImportSize = 10 << 20
+
+type taskEncoder interface {
+	Encode(task *models.Task) error
+	Close() error
+}
+
+type transferFormat struct {
+	contentType string
+	filename    string
+	encoder     func(w io.Writer) taskEncoder
+	decode      func(r io.Reader) ([]models.Task, []models.ImportProblem, error)
+}
+
+var transferFormats = map[string]transferFormat{
+	"todotxt": {
+		contentType: "text/plain; charset=UTF-8",
+		filename:    "todo.txt",
+		encoder:     func(w io.Writer) taskEncoder { return todotxt.NewEncoder(w) },
+		decode:      todotxt.Decode,
+	},
+	"json": {
+		contentType: "application/json; charset=UTF-8",
+		filename:    "tasks.json",
+		encoder:     func(w io.Writer) taskEncoder { return backup.NewJSONEncoder(w) },
+		decode:      withoutProblems(backup.DecodeJSON),
+	},
+	"csv": {
+		contentType: "text/csv; charset=UTF-8",
+		filename:    "tasks.csv",
+		encoder:     func(w io.Writer) taskEncoder { return backup.NewCSVEncoder(w) },
+		decode:      withoutProblems(backup.DecodeCSV),
+	},
+}
+
+func withoutProblems(
+	decode func(r io.Reader) ([]models.Task, error),
+) func(r io.Reader) ([]models.Task, []models.ImportProblem, error) {
+	return func(r io.Reader) ([]models.Task, []models.ImportProblem, error) {
+		tasks, err := decode(r)
+
+		return tasks, nil, err
+	}
+}
+
+// GetExport выгружает все задачи в формате из параметра format (todotxt, json, csv).
+// Задачи пишутся в ответ по мере чтения из базы.
+func (s *TodoTaskServer) GetExport(res http.ResponseWriter, req *http.Request) {
+	format, ok := transferFormats[req.FormValue("format")]
+	if !ok {
+		logctx.Warn(req.Context(), "unsupported export format", "format", req.FormValue("format"))
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res.Header().Set("Content-type", format.contentType)
+	res.Header().Set("Content-Disposition", `attachment; filename="`+format.filename+`"`)
+	res.WriteHeader(http.StatusOK)
+
+	// После начала ответа статус уже не изменить, поэтому ошибки только логируются.
+	enc := format.encoder(res)
+	if err := s.todoTaskUsecase.ExportTasks(req.Context(), enc.Encode); err != nil {
+		logctx.Error(req.Context(), "export interrupted", "error", err)
+		return
+	}
+
+	if err := enc.Close(); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		return
+	}
+}
+
+// PostImport импортирует задачи в формате из параметра format (todotxt, json, csv).
+// Параметр mode=merge|replace задаёт режим, dry_run=true — проверку без сохранения.
+// Это восстановление из выгрузки GetExport, поэтому даты задач не переносятся.
+func (s *TodoTaskServer) PostImport(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	format, ok := transferFormats[req.FormValue("format")]
+	if !ok {
+		logctx.Warn(req.Context(), "unsupported import format", "format", req.FormValue("format"))
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dryRun, _, err := boolParam(req, "dry_run")
+	if err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tasks, problems, err := format.decode(http.MaxBytesReader(res, req.Body, maxImportSize))
+	if err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	options := &models.ImportOptions{Mode: req.FormValue("mode"), DryRun: dryRun, Restore: true}
+	s.writeImportResult(res, req, tasks, problems, options)
+}
+
+// PostImportICS импортирует задачи из файла iCalendar. С параметром dry_run=true
+// возвращает результат разбора без сохранения.
+func (s *TodoTaskServer) PostImportICS(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	dryRun, _, err := boolParam(req, "dry_run")
+	if err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tasks, problems, err := ical.Decode(http.MaxBytesReader(res, req.Body, maxImportSize))
+	if err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	options := &models.ImportOptions{Mode: models.ImportModeMerge, DryRun: dryRun}
+	s.writeImportResult(res, req, tasks, problems, options)
+}
+
+func (s *TodoTaskServer) writeImportResult(
+	res http.ResponseWriter,
+	req *http.Request,
+	tasks []models.Task,
+	problems []models.ImportProblem,
+	options *models.ImportOptions,
+) {
+	status := http.StatusOK
+
+	result, err := s.todoTaskUsecase.ImportTasks(req.Context(), tasks, problems, options)
+	if err != nil {
+		switch {
+		case errors.Is(err, errorspkg.ErrBadRequest) && result != nil:
+			// Отклонённый replace: в ответе список проблем, из-за которых ничего не сохранено.
+			status = http.StatusBadRequest
+		case errors.Is(err, errorspkg.ErrBadRequest):
+			http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+			return
+		default:
+			http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	describeRepeats(result.Tasks, requestLanguage(req))
+
+	res.WriteHeader(status)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(result); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/utils/datevalidating"
+)
+
+var supportedLanguages = map[string]datevalidating.Language{
+	"ru": datevalidating.LanguageRU,
+	"en": datevalidating.LanguageEN,
+}
+
+// requestLanguage выбирает поддерживаемый язык с наибольшим весом из Accept-Language.
+func requestLanguage(req *http.Request) datevalidating.Language {
+	lang, best := datevalidating.DefaultLanguage, 0.0
+
+	for _, part := range strings.Split(req.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+
+		l, ok := supportedLanguages[base]
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if v, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+
+			q = parsed
+		}
+
+		if q > best {
+			lang, best = l, q
+		}
+	}
+
+	return lang
+}
+
+func describeRepeats(tasks []models.Task, lang datevalidating.Language) {
+	for i := range tasks {
+		tasks[i].RepeatDescription = datevalidating.DescribeRepeat(tasks[i].Repeat, lang)
+	}
+}
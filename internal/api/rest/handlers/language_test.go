@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sater-151/todo-list/internal/utils/datevalidating"
+)
+
+func TestRequestLanguage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		header string
+		want   datevalidating.Language
+	}{
+		{header: "", want: datevalidating.DefaultLanguage},
+		{header: "en", want: datevalidating.LanguageEN},
+		{header: "en-US,en;q=0.9", want: datevalidating.LanguageEN},
+		{header: "ru-RU, ru;q=0.9, en;q=0.8", want: datevalidating.LanguageRU},
+		{header: "de-DE, en;q=0.5, ru;q=0.7", want: datevalidating.LanguageRU},
+		{header: "fr, de;q=0.8", want: datevalidating.DefaultLanguage},
+		{header: "en;q=abc, ru;q=0.1", want: datevalidating.LanguageRU},
+		{header: "EN-gb;q=0.3", want: datevalidating.LanguageEN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+			if tt.header != "" {
+				req.Header.Set("Accept-Language", tt.header)
+			}
+
+			if got := requestLanguage(req); got != tt.want {
+				t.Errorf("requestLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bytedance/sonic"
+	"github.com/go-chi/chi/v5"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+type (
+	IReminderUsecase interface {
+		AddReminder(ctx context.Context, reminder *models.Reminder) (*models.Reminder, error)
+		DeleteReminder(ctx context.Context, uuid string) error
+		ListReminders(ctx context.Context, taskID string) ([]models.Reminder, error)
+	}
+)
+
+type ReminderServerDependencies struct {
+	ReminderUsecase IReminderUsecase `validate:"required"`
+}
+
+type ReminderServer struct {
+	reminderUsecase IReminderUsecase
+}
+
+func NewReminderHandlers(d *ReminderServerDependencies) (*ReminderServer, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("rest.NewReminderHandlers", d, err)
+	}
+
+	return &ReminderServer{
+		reminderUsecase: d.ReminderUsecase,
+	}, nil
+}
+
+func (s *ReminderServer) PostReminder(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	var reminder models.Reminder
+	if err := sonic.ConfigDefault.NewDecoder(req.Body).Decode(&reminder); err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := s.reminderUsecase.AddReminder(req.Context(), &reminder)
+	if err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(created); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// ListReminders возвращает напоминания; параметр task_id оставляет только напоминания задачи.
+func (s *ReminderServer) ListReminders(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	reminders, err := s.reminderUsecase.ListReminders(req.Context(), req.FormValue("task_id"))
+	if err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	if reminders == nil {
+		reminders = []models.Reminder{}
+	}
+
+	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(models.ListReminder{Reminders: reminders}); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *ReminderServer) DeleteReminder(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	if err := s.reminderUsecase.DeleteReminder(req.Context(), chi.URLParam(req, "id")); err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	res.WriteHeader(http.StatusOK)
+}
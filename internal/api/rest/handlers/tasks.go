@@ -4,14 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/sater-151/todo-list/internal/models"
 	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
 	"github.com/sater-151/todo-list/internal/pkg/validate"
+	"github.com/sater-151/todo-list/internal/utils/datevalidating"
+	"github.com/sater-151/todo-list/internal/utils/ical"
 	"github.com/sater-151/todo-list/internal/utils/selectconfig"
 )
 
@@ -23,6 +28,14 @@ type (
 		UpdateTask(ctx context.Context, task *models.Task) error
 		DeleteTask(ctx context.Context, uuid string) error
 		Select(ctx context.Context, selectConfig *models.SelectConfig) ([]models.Task, error)
+		ParseQuickTask(ctx context.Context, text string) (*models.Task, error)
+		Calendar(ctx context.Context, calendarRange *models.CalendarRange) (*models.Calendar, error)
+		ImportTasks(
+			ctx context.Context, tasks []models.Task, problems []models.ImportProblem, options *models.ImportOptions,
+		) (*models.ImportResult, error)
+		ExportTasks(ctx context.Context, fn func(*models.Task) error) error
+		ListMissed(ctx context.Context, taskID string, limit int) ([]models.MissedTask, error)
+		SnoozeTask(ctx context.Context, snooze *models.Snooze) (*models.Task, error)
 	}
 )
 
@@ -52,7 +65,7 @@ func (s *TodoTaskServer) PostTask(res http.ResponseWriter, req *http.Request) {
 
 	var task models.Task
 	if err := sonic.ConfigDefault.NewDecoder(req.Body).Decode(&task); err != nil {
-		slog.Warn(err.Error())
+		logctx.Warn(req.Context(), err.Error())
 		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
 		return
 	}
@@ -70,20 +83,59 @@ func (s *TodoTaskServer) PostTask(res http.ResponseWriter, req *http.Request) {
 
 	res.WriteHeader(http.StatusOK)
 	if err := sonic.ConfigDefault.NewEncoder(res).Encode(id); err != nil {
-		slog.Error(err.Error())
+		logctx.Error(req.Context(), err.Error())
 		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusBadRequest)
 		return
 	}
 
 }
 
+func (s *TodoTaskServer) PostQuickTask(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	var quick models.QuickTask
+	if err := sonic.ConfigDefault.NewDecoder(req.Body).Decode(&quick); err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	task, err := s.todoTaskUsecase.ParseQuickTask(req.Context(), quick.Text)
+	if err != nil {
+		if errors.Is(err, errorspkg.ErrBadRequest) {
+			http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	task.RepeatDescription = datevalidating.DescribeRepeat(task.Repeat, requestLanguage(req))
+
+	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(task); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 func (s *TodoTaskServer) ListTask(res http.ResponseWriter, req *http.Request) {
 	res.Header().Set("Content-type", "application/json; charset=UTF-8")
 
-	search := req.FormValue("search")
-	selectConfig := selectconfig.Default()
-	if search != "" {
-		selectConfig.Search = search
+	filter, err := parseTaskFilter(req)
+	if err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	selectConfig, err := selectconfig.FromFilter(filter, time.Now())
+	if err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
 	}
 
 	tasks, err := s.todoTaskUsecase.GetListTask(req.Context(), selectConfig)
@@ -97,19 +149,71 @@ func (s *TodoTaskServer) ListTask(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	describeRepeats(tasks, requestLanguage(req))
+
 	res.WriteHeader(http.StatusOK)
 	if err := sonic.ConfigDefault.NewEncoder(res).Encode(models.ListTask{Tasks: tasks}); err != nil {
-		slog.Error(err.Error())
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *TodoTaskServer) GetCalendar(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	calendar, err := s.todoTaskUsecase.Calendar(req.Context(), &models.CalendarRange{
+		From: req.FormValue("from"),
+		To:   req.FormValue("to"),
+	})
+	if err != nil {
+		if errors.Is(err, errorspkg.ErrBadRequest) {
+			http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(calendar); err != nil {
+		logctx.Error(req.Context(), err.Error())
 		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
+func (s *TodoTaskServer) GetCalendarICS(res http.ResponseWriter, req *http.Request) {
+	component := ical.ComponentEvent
+	if strings.EqualFold(req.FormValue("type"), "vtodo") {
+		component = ical.ComponentTodo
+	}
+
+	selectConfig := selectconfig.Default()
+	selectConfig.Limit = ""
+
+	tasks, err := s.todoTaskUsecase.Select(req.Context(), selectConfig)
+	if err != nil {
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res.Header().Set("Content-type", "text/calendar; charset=UTF-8")
+	res.Header().Set("Content-Disposition", `inline; filename="tasks.ics"`)
+	res.WriteHeader(http.StatusOK)
+
+	if err := ical.Encode(res, tasks, component, time.Now()); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		return
+	}
+}
+
 func (s *TodoTaskServer) GetTask(res http.ResponseWriter, req *http.Request) {
 	res.Header().Set("Content-type", "application/json; charset=UTF-8")
 	id := req.FormValue("id")
 	if id == "" {
-		slog.Warn("id required")
+		logctx.Warn(req.Context(), "id required")
 		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
 		return
 	}
@@ -133,21 +237,58 @@ func (s *TodoTaskServer) GetTask(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	describeRepeats(tasks, requestLanguage(req))
+
 	res.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(res).Encode(tasks[0]); err != nil {
-		slog.Error(err.Error())
+		logctx.Error(req.Context(), err.Error())
 		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
 		return
 	}
 
 }
 
+// ListMissed возвращает даты повторяющихся задач, пропущенные при переносе вперёд.
+// Параметры: task_id — только для одной задачи, limit — число записей.
+func (s *TodoTaskServer) ListMissed(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	var limit int
+	if v := req.FormValue("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			logctx.Warn(req.Context(), errorspkg.NewStrconvError("ListMissed", v, err).Error())
+			http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+			return
+		}
+
+		limit = n
+	}
+
+	missed, err := s.todoTaskUsecase.ListMissed(req.Context(), req.FormValue("task_id"), limit)
+	if err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	if missed == nil {
+		missed = []models.MissedTask{}
+	}
+
+	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(models.ListMissedTask{Missed: missed}); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 func (s *TodoTaskServer) PutTask(res http.ResponseWriter, req *http.Request) {
 	res.Header().Set("Content-type", "application/json; charset=UTF-8")
 
 	var task models.Task
 	if err := sonic.ConfigDefault.NewDecoder(req.Body).Decode(&task); err != nil {
-		slog.Warn(err.Error())
+		logctx.Warn(req.Context(), err.Error())
 		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
 		return
 	}
@@ -172,7 +313,7 @@ func (s *TodoTaskServer) PostTaskDone(res http.ResponseWriter, req *http.Request
 	id := req.FormValue("id")
 	selectConfig := selectconfig.Default()
 	if id == "" {
-		slog.Warn("id required")
+		logctx.Warn(req.Context(), "id required")
 		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
 		return
 	}
@@ -181,23 +322,41 @@ func (s *TodoTaskServer) PostTaskDone(res http.ResponseWriter, req *http.Request
 
 	err := s.todoTaskUsecase.TaskDone(req.Context(), selectConfig)
 	if err != nil {
-		if errors.Is(err, errorspkg.ErrBadRequest) {
-			http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
-		} else {
-			http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
-		}
+		usecaseError(res, err)
+		return
+	}
+
+	res.WriteHeader(http.StatusOK)
+}
 
+// PostSnoozeTask откладывает задачу id до даты until (ГГГГММДД) или на срок for (2d, 1w, 1m)
+// и возвращает задачу с новой датой.
+func (s *TodoTaskServer) PostSnoozeTask(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	task, err := s.todoTaskUsecase.SnoozeTask(req.Context(), &models.Snooze{
+		ID:    req.FormValue("id"),
+		Until: req.FormValue("until"),
+		For:   req.FormValue("for"),
+	})
+	if err != nil {
+		usecaseError(res, err)
 		return
 	}
 
 	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(task); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
 }
 
 func (s *TodoTaskServer) DeleteTask(res http.ResponseWriter, req *http.Request) {
 	res.Header().Set("Content-type", "application/json; charset=UTF-8")
 	id := req.FormValue("id")
 	if id == "" {
-		slog.Warn("id required")
+		logctx.Warn(req.Context(), "id required")
 		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
 		return
 	}
@@ -222,27 +381,27 @@ func (s *TodoTaskServer) Sign(res http.ResponseWriter, req *http.Request) {
 
 	var passJS models.PasswordJS
 	if err := sonic.ConfigDefault.NewDecoder(req.Body).Decode(&passJS); err != nil {
-		slog.Warn("password required")
+		logctx.Warn(req.Context(), "password required")
 		http.Error(res, "password required", http.StatusBadRequest)
 		return
 	}
 
 	if passTrue != passJS.Pass {
-		slog.Warn("wrong password")
+		logctx.Warn(req.Context(), "wrong password")
 		http.Error(res, "wrong password", http.StatusBadRequest)
 		return
 	}
 
 	token, err := jwt.New(jwt.SigningMethodHS256).SignedString([]byte(passTrue))
 	if err != nil {
-		slog.Error(err.Error())
+		logctx.Error(req.Context(), err.Error())
 		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
 		return
 	}
 
 	res.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(res).Encode(models.JWTToken{Token: token}); err != nil {
-		slog.Error(err.Error())
+		logctx.Error(req.Context(), err.Error())
 		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+	"github.com/go-chi/chi/v5"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+type (
+	IViewUsecase interface {
+		AddView(ctx context.Context, view *models.View) (string, error)
+		UpdateView(ctx context.Context, view *models.View) error
+		DeleteView(ctx context.Context, uuid string) error
+		ListViews(ctx context.Context) ([]models.View, error)
+		ViewTasks(ctx context.Context, uuid string, limit, offset int) (*models.ViewTasks, error)
+	}
+)
+
+type ViewServerDependencies struct {
+	ViewUsecase IViewUsecase `validate:"required"`
+}
+
+type ViewServer struct {
+	viewUsecase IViewUsecase
+}
+
+func NewViewHandlers(d *ViewServerDependencies) (*ViewServer, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("rest.NewViewHandlers", d, err)
+	}
+
+	return &ViewServer{
+		viewUsecase: d.ViewUsecase,
+	}, nil
+}
+
+func (s *ViewServer) PostView(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	var view models.View
+	if err := sonic.ConfigDefault.NewDecoder(req.Body).Decode(&view); err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := s.viewUsecase.AddView(req.Context(), &view)
+	if err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(models.ID{ID: id}); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *ViewServer) ListViews(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	views, err := s.viewUsecase.ListViews(req.Context())
+	if err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	if views == nil {
+		views = []models.View{}
+	}
+
+	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(models.ListView{Views: views}); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *ViewServer) PutView(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	var view models.View
+	if err := sonic.ConfigDefault.NewDecoder(req.Body).Decode(&view); err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	view.ID = chi.URLParam(req, "id")
+
+	if err := s.viewUsecase.UpdateView(req.Context(), &view); err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	res.WriteHeader(http.StatusOK)
+}
+
+func (s *ViewServer) DeleteView(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	if err := s.viewUsecase.DeleteView(req.Context(), chi.URLParam(req, "id")); err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	res.WriteHeader(http.StatusOK)
+}
+
+// ViewTasks возвращает страницу задач представления. Параметры: limit — число
+// задач, offset — смещение; next_offset в ответе указывает на следующую страницу.
+func (s *ViewServer) ViewTasks(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	limit, err := formInt(req, "limit")
+	if err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	offset, err := formInt(req, "offset")
+	if err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tasks, err := s.viewUsecase.ViewTasks(req.Context(), chi.URLParam(req, "id"), limit, offset)
+	if err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	if tasks.Tasks == nil {
+		tasks.Tasks = []models.Task{}
+	}
+
+	describeRepeats(tasks.Tasks, requestLanguage(req))
+
+	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(tasks); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// formInt возвращает целый параметр запроса key или 0, если он не задан.
+func formInt(req *http.Request, key string) (int, error) {
+	v := req.FormValue(key)
+	if v == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errorspkg.NewStrconvError("formInt", v, err)
+	}
+
+	return n, nil
+}
+
+func usecaseError(res http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errorspkg.ErrBadRequest):
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+	case errors.Is(err, errorspkg.ErrNotFound):
+		http.Error(res, errorspkg.ErrNotFound.Error(), http.StatusNotFound)
+	default:
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+	"github.com/go-chi/chi/v5"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+type (
+	IWebhookUsecase interface {
+		AddWebhook(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error)
+		UpdateWebhook(ctx context.Context, webhook *models.Webhook) error
+		DeleteWebhook(ctx context.Context, uuid string) error
+		ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+		ListDeliveries(ctx context.Context, uuid string, limit int) ([]models.WebhookDelivery, error)
+	}
+)
+
+type WebhookServerDependencies struct {
+	WebhookUsecase IWebhookUsecase `validate:"required"`
+}
+
+type WebhookServer struct {
+	webhookUsecase IWebhookUsecase
+}
+
+func NewWebhookHandlers(d *WebhookServerDependencies) (*WebhookServer, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("rest.NewWebhookHandlers", d, err)
+	}
+
+	return &WebhookServer{
+		webhookUsecase: d.WebhookUsecase,
+	}, nil
+}
+
+func (s *WebhookServer) PostWebhook(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	var webhook models.Webhook
+	if err := sonic.ConfigDefault.NewDecoder(req.Body).Decode(&webhook); err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := s.webhookUsecase.AddWebhook(req.Context(), &webhook)
+	if err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(created); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *WebhookServer) ListWebhooks(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	webhooks, err := s.webhookUsecase.ListWebhooks(req.Context())
+	if err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	if webhooks == nil {
+		webhooks = []models.Webhook{}
+	}
+
+	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(models.ListWebhook{Webhooks: webhooks}); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *WebhookServer) PutWebhook(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	var webhook models.Webhook
+	if err := sonic.ConfigDefault.NewDecoder(req.Body).Decode(&webhook); err != nil {
+		logctx.Warn(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	webhook.ID = chi.URLParam(req, "id")
+
+	if err := s.webhookUsecase.UpdateWebhook(req.Context(), &webhook); err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	res.WriteHeader(http.StatusOK)
+}
+
+func (s *WebhookServer) DeleteWebhook(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	if err := s.webhookUsecase.DeleteWebhook(req.Context(), chi.URLParam(req, "id")); err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	res.WriteHeader(http.StatusOK)
+}
+
+func (s *WebhookServer) ListDeliveries(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-type", "application/json; charset=UTF-8")
+
+	var limit int
+	if v := req.FormValue("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			logctx.Warn(req.Context(), errorspkg.NewStrconvError("ListDeliveries", v, err).Error())
+			http.Error(res, errorspkg.ErrBadRequest.Error(), http.StatusBadRequest)
+			return
+		}
+
+		limit = n
+	}
+
+	deliveries, err := s.webhookUsecase.ListDeliveries(req.Context(), chi.URLParam(req, "id"), limit)
+	if err != nil {
+		usecaseError(res, err)
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+
+	res.WriteHeader(http.StatusOK)
+	if err := sonic.ConfigDefault.NewEncoder(res).Encode(models.ListWebhookDelivery{Deliveries: deliveries}); err != nil {
+		logctx.Error(req.Context(), err.Error())
+		http.Error(res, errorspkg.ErrInternalError.Error(), http.StatusInternalServerError)
+		return
+	}
+}
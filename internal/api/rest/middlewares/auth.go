@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -9,15 +10,25 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/sater-151/todo-list/internal/models"
 	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
 	"github.com/sater-151/todo-list/internal/pkg/validate"
 )
 
+type (
+	// IFeedTokens проверяет секрет календарного фида.
+	IFeedTokens interface {
+		CheckToken(ctx context.Context, token string) (bool, error)
+	}
+)
+
 type MiddlewaresDependencies struct {
-	Password string `validate:"required"`
+	Password   string      `validate:"required"`
+	FeedTokens IFeedTokens `validate:"required"`
 }
 
 type Middlewares struct {
-	password string
+	password   string
+	feedTokens IFeedTokens
 }
 
 func NewMiddlewares(d *MiddlewaresDependencies) (*Middlewares, error) {
@@ -26,7 +37,8 @@ func NewMiddlewares(d *MiddlewaresDependencies) (*Middlewares, error) {
 	}
 
 	return &Middlewares{
-		password: d.Password,
+		password:   d.Password,
+		feedTokens: d.FeedTokens,
 	}, nil
 }
 
@@ -36,7 +48,7 @@ func (m *Middlewares) Auth(next http.Handler) http.Handler {
 		if pass != "" {
 			cookie, err := req.Cookie("token")
 			if err != nil {
-				slog.Error(err.Error())
+				logctx.Error(req.Context(), err.Error())
 				ErrorHandler(res, err, http.StatusUnauthorized)
 				return
 			}
@@ -45,23 +57,45 @@ func (m *Middlewares) Auth(next http.Handler) http.Handler {
 				return []byte(pass), nil
 			})
 			if err != nil {
-				slog.Error(err.Error())
+				logctx.Error(req.Context(), err.Error())
 				ErrorHandler(res, err, http.StatusUnauthorized)
 				return
 			}
 			if !jwtToken.Valid {
-				slog.Error("token is invalid")
+				logctx.Error(req.Context(), "token is invalid")
 				ErrorHandler(res, fmt.Errorf("token is invalid"), http.StatusUnauthorized)
 				return
 			}
 		}
-		next.ServeHTTP(res, req)
+		next.ServeHTTP(res, authenticated(req, userOwner))
 	}
 
 	return http.HandlerFunc(fn)
 
 }
 
+// FeedAuth пропускает запросы с секретом фида в параметре token: календарные
+// приложения не умеют передавать cookie с JWT.
+func (m *Middlewares) FeedAuth(next http.Handler) http.Handler {
+	fn := func(res http.ResponseWriter, req *http.Request) {
+		ok, err := m.feedTokens.CheckToken(req.Context(), req.URL.Query().Get("token"))
+		if err != nil {
+			ErrorHandler(res, err, http.StatusInternalServerError)
+			return
+		}
+
+		if !ok {
+			logctx.Warn(req.Context(), "invalid feed token")
+			ErrorHandler(res, fmt.Errorf("invalid feed token"), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(res, authenticated(req, userFeed))
+	}
+
+	return http.HandlerFunc(fn)
+}
+
 func ErrorHandler(res http.ResponseWriter, err error, status int) {
 	var errJS models.Error
 	errJS.Err = err.Error()
@@ -0,0 +1,97 @@
+package middlewares
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+)
+
+const (
+	HeaderRequestID = "X-Request-ID"
+
+	userOwner = "owner"
+	userFeed  = "feed"
+)
+
+// Идентификатор запроса от клиента или прокси принимается, только если он не
+// испортит журнал.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+type accessKey struct{}
+
+// access — данные для журнала доступа, которые заполняются внутри цепочки обработчиков.
+type access struct {
+	user string
+}
+
+// RequestID берёт идентификатор запроса из X-Request-ID или создаёт новый, возвращает
+// его в ответе и кладёт в контекст журнал с полем request_id.
+func (m *Middlewares) RequestID(next http.Handler) http.Handler {
+	fn := func(res http.ResponseWriter, req *http.Request) {
+		id := req.Header.Get(HeaderRequestID)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+		}
+
+		res.Header().Set(HeaderRequestID, id)
+
+		ctx := logctx.With(req.Context(), slog.String("request_id", id))
+		next.ServeHTTP(res, req.WithContext(ctx))
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// AccessLog пишет по записи на каждый запрос. Ответы 5xx пишутся с уровнем Error.
+func (m *Middlewares) AccessLog(next http.Handler) http.Handler {
+	fn := func(res http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		entry := &access{}
+		ww := middleware.NewWrapResponseWriter(res, req.ProtoMajor)
+
+		next.ServeHTTP(ww, req.WithContext(context.WithValue(req.Context(), accessKey{}, entry)))
+
+		route := ""
+		if rctx := chi.RouteContext(req.Context()); rctx != nil {
+			route = rctx.RoutePattern()
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		logctx.From(req.Context()).LogAttrs(req.Context(), level, "http request",
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+			slog.String("route", route),
+			slog.Int("status", status),
+			slog.Int("bytes", ww.BytesWritten()),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("user", entry.user),
+		)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// authenticated отмечает пользователя запроса в журнале доступа и в журнале контекста.
+func authenticated(req *http.Request, user string) *http.Request {
+	if entry, ok := req.Context().Value(accessKey{}).(*access); ok {
+		entry.user = user
+	}
+
+	return req.WithContext(logctx.With(req.Context(), slog.String("user", user)))
+}
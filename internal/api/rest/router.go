@@ -6,16 +6,41 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/tracing"
 	"github.com/sater-151/todo-list/internal/pkg/validate"
 )
 
 const (
-	webDir       = "web"
-	PathAPI      = "/api"
-	PathTasks    = "/tasks"
-	PathTask     = "/task"
-	PathTaskDone = "/task/done"
-	PathSignin   = "/signin"
+	webDir        = "web"
+	PathAPI       = "/api"
+	PathTasks     = "/tasks"
+	PathTask      = "/task"
+	PathTaskDone  = "/task/done"
+	PathTaskQuick = "/task/quick"
+	PathSnooze    = "/task/snooze"
+	PathMissed    = "/tasks/missed"
+	PathSignin    = "/signin"
+	PathViews     = "/views"
+	PathView      = "/views/{id}"
+	PathViewTasks = "/views/{id}/tasks"
+	PathCalendar  = "/calendar"
+	PathICS       = "/calendar.ics"
+	PathFeedToken = "/calendar/token"
+	PathImportICS = "/import/ics"
+	PathImport    = "/import"
+	PathExport    = "/export"
+	PathEvents    = "/events"
+
+	PathWebhooks          = "/webhooks"
+	PathWebhook           = "/webhooks/{id}"
+	PathWebhookDeliveries = "/webhooks/{id}/deliveries"
+
+	PathReminders = "/reminders"
+	PathReminder  = "/reminders/{id}"
+
+	PathMetrics = "/metrics"
+	PathHealthz = "/healthz"
+	PathReadyz  = "/readyz"
 )
 
 type (
@@ -23,21 +48,84 @@ type (
 		PostTask(res http.ResponseWriter, req *http.Request)
 		ListTask(res http.ResponseWriter, req *http.Request)
 		GetTask(res http.ResponseWriter, req *http.Request)
+		GetCalendar(res http.ResponseWriter, req *http.Request)
+		GetCalendarICS(res http.ResponseWriter, req *http.Request)
 		PutTask(res http.ResponseWriter, req *http.Request)
 		PostTaskDone(res http.ResponseWriter, req *http.Request)
+		PostQuickTask(res http.ResponseWriter, req *http.Request)
+		PostSnoozeTask(res http.ResponseWriter, req *http.Request)
+		PostImportICS(res http.ResponseWriter, req *http.Request)
+		PostImport(res http.ResponseWriter, req *http.Request)
+		GetExport(res http.ResponseWriter, req *http.Request)
+		ListMissed(res http.ResponseWriter, req *http.Request)
 		DeleteTask(res http.ResponseWriter, req *http.Request)
 		Sign(res http.ResponseWriter, req *http.Request)
 	}
 
+	IViewHandlers interface {
+		PostView(res http.ResponseWriter, req *http.Request)
+		ListViews(res http.ResponseWriter, req *http.Request)
+		PutView(res http.ResponseWriter, req *http.Request)
+		DeleteView(res http.ResponseWriter, req *http.Request)
+		ViewTasks(res http.ResponseWriter, req *http.Request)
+	}
+
+	IFeedHandlers interface {
+		GetFeedToken(res http.ResponseWriter, req *http.Request)
+		PostFeedToken(res http.ResponseWriter, req *http.Request)
+	}
+
+	IEventHandlers interface {
+		Events(res http.ResponseWriter, req *http.Request)
+	}
+
+	IWebhookHandlers interface {
+		PostWebhook(res http.ResponseWriter, req *http.Request)
+		ListWebhooks(res http.ResponseWriter, req *http.Request)
+		PutWebhook(res http.ResponseWriter, req *http.Request)
+		DeleteWebhook(res http.ResponseWriter, req *http.Request)
+		ListDeliveries(res http.ResponseWriter, req *http.Request)
+	}
+
+	IReminderHandlers interface {
+		PostReminder(res http.ResponseWriter, req *http.Request)
+		ListReminders(res http.ResponseWriter, req *http.Request)
+		DeleteReminder(res http.ResponseWriter, req *http.Request)
+	}
+
+	IHealthHandlers interface {
+		Healthz(res http.ResponseWriter, req *http.Request)
+		Readyz(res http.ResponseWriter, req *http.Request)
+	}
+
+	IMetrics interface {
+		Middleware(n http.Handler) http.Handler
+		Handler() http.Handler
+	}
+
 	IInternalMW interface {
+		RequestID(n http.Handler) http.Handler
+		AccessLog(n http.Handler) http.Handler
 		Auth(n http.Handler) http.Handler
+		FeedAuth(n http.Handler) http.Handler
 	}
 )
 
 type (
 	RouterDependencies struct {
-		Handlers   ITodoTaskHandlers
-		InternalMW IInternalMW
+		Handlers         ITodoTaskHandlers
+		ViewHandlers     IViewHandlers
+		FeedHandlers     IFeedHandlers
+		EventHandlers    IEventHandlers
+		WebhookHandlers  IWebhookHandlers
+		ReminderHandlers IReminderHandlers
+		HealthHandlers   IHealthHandlers
+		InternalMW       IInternalMW
+		Metrics          IMetrics
+	}
+
+	AdminRouterDependencies struct {
+		Metrics IMetrics
 	}
 )
 
@@ -48,10 +136,16 @@ func NewRouter(d *RouterDependencies) (http.Handler, error) {
 
 	r := chi.NewRouter()
 	r.Use(
-		middleware.Logger,
+		tracing.Middleware,
+		d.InternalMW.RequestID,
+		d.InternalMW.AccessLog,
 		middleware.Recoverer,
+		d.Metrics.Middleware,
 	)
 
+	r.Get(PathHealthz, d.HealthHandlers.Healthz)
+	r.Get(PathReadyz, d.HealthHandlers.Readyz)
+
 	// --- API ---
 	apiR := r.Route(PathAPI, func(r chi.Router) {})
 
@@ -61,14 +155,56 @@ func NewRouter(d *RouterDependencies) (http.Handler, error) {
 
 	authR.Get(PathTasks, d.Handlers.ListTask)
 	authR.Get(PathTask, d.Handlers.GetTask)
+	authR.Get(PathMissed, d.Handlers.ListMissed)
+	authR.Get(PathCalendar, d.Handlers.GetCalendar)
+	authR.Get(PathFeedToken, d.FeedHandlers.GetFeedToken)
+	authR.Post(PathFeedToken, d.FeedHandlers.PostFeedToken)
+	authR.Get(PathExport, d.Handlers.GetExport)
+	authR.Get(PathEvents, d.EventHandlers.Events)
+
+	apiR.With(d.InternalMW.FeedAuth).Get(PathICS, d.Handlers.GetCalendarICS)
 
 	authR.Post(PathTask, d.Handlers.PostTask)
 	authR.Post(PathTaskDone, d.Handlers.PostTaskDone)
+	authR.Post(PathTaskQuick, d.Handlers.PostQuickTask)
+	authR.Post(PathSnooze, d.Handlers.PostSnoozeTask)
+	authR.Post(PathImportICS, d.Handlers.PostImportICS)
+	authR.Post(PathImport, d.Handlers.PostImport)
 
 	authR.Put(PathTask, d.Handlers.PutTask)
 	authR.Delete(PathTask, d.Handlers.DeleteTask)
 
+	authR.Get(PathViews, d.ViewHandlers.ListViews)
+	authR.Post(PathViews, d.ViewHandlers.PostView)
+	authR.Put(PathView, d.ViewHandlers.PutView)
+	authR.Delete(PathView, d.ViewHandlers.DeleteView)
+	authR.Get(PathViewTasks, d.ViewHandlers.ViewTasks)
+
+	authR.Get(PathWebhooks, d.WebhookHandlers.ListWebhooks)
+	authR.Post(PathWebhooks, d.WebhookHandlers.PostWebhook)
+	authR.Put(PathWebhook, d.WebhookHandlers.PutWebhook)
+	authR.Delete(PathWebhook, d.WebhookHandlers.DeleteWebhook)
+	authR.Get(PathWebhookDeliveries, d.WebhookHandlers.ListDeliveries)
+
+	authR.Get(PathReminders, d.ReminderHandlers.ListReminders)
+	authR.Post(PathReminders, d.ReminderHandlers.PostReminder)
+	authR.Delete(PathReminder, d.ReminderHandlers.DeleteReminder)
+
 	r.Handle("/*", http.FileServer(http.Dir(webDir)))
 
 	return r, nil
 }
+
+// NewAdminRouter возвращает маршруты служебного сервера, недоступные на основном порту.
+func NewAdminRouter(d *AdminRouterDependencies) (http.Handler, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("rest.NewAdminRouter", d, err)
+	}
+
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer)
+
+	r.Method(http.MethodGet, PathMetrics, d.Metrics.Handler())
+
+	return r, nil
+}
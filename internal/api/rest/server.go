@@ -52,10 +52,14 @@ func (s *Server) Start(ctx context.Context) error {
 	case err := <-errChan:
 		return err
 	case <-ctx.Done():
-		return s.Stop(ctx)
+		return s.Stop(context.WithoutCancel(ctx))
 	}
 }
 
+// Stop дожидается завершения текущих запросов, но не дольше shutdownTimeout.
 func (s *Server) Stop(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.shutdownTimeout)
+	defer cancel()
+
 	return s.srvHTTP.Shutdown(ctx)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/sater-151/todo-list/internal/api/rest"
 	"github.com/sater-151/todo-list/internal/api/rest/handlers"
@@ -13,20 +14,38 @@ import (
 	"github.com/sater-151/todo-list/internal/configuration"
 	"github.com/sater-151/todo-list/internal/credentials"
 	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/eventbus"
+	"github.com/sater-151/todo-list/internal/pkg/metrics"
+	"github.com/sater-151/todo-list/internal/pkg/notifier"
+	"github.com/sater-151/todo-list/internal/pkg/tracing"
 	"github.com/sater-151/todo-list/internal/pkg/validate"
+	"github.com/sater-151/todo-list/internal/usecases"
 )
 
 type (
+	// Dependencies: ConfigPath — файл настроек, изменения которого применяются
+	// без перезапуска; пустой путь отключает перечитывание. LogLevel — уровень
+	// журнала, который меняется при перечитывании.
 	Dependencies struct {
 		Configuration *configuration.Configurations `validate:"required"`
 		Credentials   *credentials.Credentials      `validate:"required"`
+		ConfigPath    string                        `validate:"-"`
+		LogLevel      *slog.LevelVar                `validate:"required"`
 	}
 
 	App struct {
+		config     *configuration.Configurations
+		configPath string
+		logLevel   *slog.LevelVar
 		repository *Repository
 		rest       *rest.Server
+		admin      *rest.Server
 		usecases   *Usecases
+		events     *eventbus.Bus
+		tracing    *tracing.Tracing
 		logger     *slog.Logger
+
+		rollForward *dailySchedule
 	}
 )
 
@@ -35,13 +54,54 @@ func New(ctx context.Context, d *Dependencies) (*App, error) {
 		return nil, errorspkg.NewValidationError("app.NewApp", d, err)
 	}
 
+	rollForward, err := newDailySchedule(d.Configuration.RollForward.RunAt, d.Configuration.RollForward.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("roll forward schedule: %w", err)
+	}
+
+	tracer, err := tracing.New(ctx, &tracing.Dependencies{
+		Exporter:    d.Configuration.Tracing.Exporter,
+		Endpoint:    d.Configuration.Tracing.Endpoint,
+		Insecure:    d.Configuration.Tracing.Insecure,
+		SampleRatio: d.Configuration.Tracing.SampleRatio,
+		Version:     d.Configuration.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	repo, err := NewRepo(ctx, *d.Credentials.Postgres)
 	if err != nil {
 		return nil, err
 	}
 
+	events := eventbus.New(eventbus.DefaultHistorySize)
+
+	m, err := metrics.New(&metrics.Dependencies{
+		Version: d.Configuration.Version,
+		Pool:    repo.Pool,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notify, err := newNotifier(d.Credentials.SMTP, d.Configuration.Reminders.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	migration, err := latestMigration()
+	if err != nil {
+		return nil, err
+	}
+
 	uc, err := NewUsecases(&UsecasesDependencies{
-		Repository: repo,
+		Repository:    repo,
+		Events:        events,
+		Notifier:      notify,
+		Metrics:       m,
+		Migration:     migration,
+		Configuration: d.Configuration,
 	})
 	if err != nil {
 		return nil, err
@@ -55,13 +115,66 @@ func New(ctx context.Context, d *Dependencies) (*App, error) {
 		return nil, err
 	}
 
+	viewHandlers, err := handlers.NewViewHandlers(&handlers.ViewServerDependencies{
+		ViewUsecase: uc.View,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eventHandlers, err := handlers.NewEventHandlers(&handlers.EventServerDependencies{
+		Events: events,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	webhookHandlers, err := handlers.NewWebhookHandlers(&handlers.WebhookServerDependencies{
+		WebhookUsecase: uc.Webhook,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reminderHandlers, err := handlers.NewReminderHandlers(&handlers.ReminderServerDependencies{
+		ReminderUsecase: uc.Reminder,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	healthHandlers, err := handlers.NewHealthHandlers(&handlers.HealthServerDependencies{
+		HealthUsecase: uc.Health,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	feedHandlers, err := handlers.NewFeedHandlers(&handlers.FeedServerDependencies{
+		FeedUsecase: uc.Feed,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	mw, err := middlewares.NewMiddlewares(&middlewares.MiddlewaresDependencies{
-		Password: d.Credentials.Data.Password,
+		Password:   d.Credentials.Data.Password,
+		FeedTokens: uc.Feed,
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	r, err := rest.NewRouter(&rest.RouterDependencies{
-		Handlers:   todoTaskHandlers,
-		InternalMW: mw,
+		Handlers:         todoTaskHandlers,
+		ViewHandlers:     viewHandlers,
+		FeedHandlers:     feedHandlers,
+		EventHandlers:    eventHandlers,
+		WebhookHandlers:  webhookHandlers,
+		ReminderHandlers: reminderHandlers,
+		HealthHandlers:   healthHandlers,
+		InternalMW:       mw,
+		Metrics:          m,
 	})
 	if err != nil {
 		return nil, err
@@ -75,24 +188,146 @@ func New(ctx context.Context, d *Dependencies) (*App, error) {
 		return nil, err
 	}
 
+	adminRouter, err := rest.NewAdminRouter(&rest.AdminRouterDependencies{
+		Metrics: m,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	adminConfig := *d.Configuration.HTTPServer
+	adminConfig.Port = d.Configuration.Admin.Port
+
+	admin, err := rest.NewServer(rest.ServerDependencies{
+		Handler: adminRouter,
+		Config:  &adminConfig,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &App{
+		config:     d.Configuration,
+		configPath: d.ConfigPath,
+		logLevel:   d.LogLevel,
 		repository: repo,
 		rest:       server,
+		admin:      admin,
 		usecases:   uc,
+		events:     events,
+		tracing:    tracer,
 		logger:     slog.With(slog.String("component", "app")),
+
+		rollForward: rollForward,
 	}, nil
 }
 
 func (a *App) Start(ctx context.Context, wg *sync.WaitGroup) <-chan error {
-	errCh := make(chan error, 3)
+	errCh := make(chan error, 4)
+
+	// Серверы останавливаются не сразу: сначала /readyz сообщает о неготовности,
+	// и за DrainDelay балансировщик успевает перестать направлять запросы.
+	serverCtx, stopServers := context.WithCancel(context.WithoutCancel(ctx))
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		a.usecases.Health.Drain()
+		a.logger.Info("draining before shutdown", slog.Duration("delay", a.config.Health.DrainDelay))
+		time.Sleep(a.config.Health.DrainDelay)
+		stopServers()
+	}()
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := a.rest.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		if err := a.rest.Start(serverCtx); err != nil && !errors.Is(err, context.Canceled) {
 			errCh <- fmt.Errorf("rest.Start error: %w", err)
 		}
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := a.admin.Start(serverCtx); err != nil && !errors.Is(err, context.Canceled) {
+			errCh <- fmt.Errorf("admin.Start error: %w", err)
+		}
+	}()
+
+	a.runTriggered(ctx, wg, "outbox", a.config.Outbox.PollInterval, a.usecases.Outbox.Wake(),
+		func(ctx context.Context) error {
+			_, err := a.usecases.Outbox.Relay(ctx)
+
+			return err
+		})
+
+	// При обрыве связи подписка на события восстанавливается через PollInterval.
+	a.runPeriodic(ctx, wg, "outbox-listen", a.config.Outbox.PollInterval, a.usecases.Outbox.Listen)
+
+	a.runPeriodic(ctx, wg, "webhooks", a.config.Webhooks.PollInterval, func(ctx context.Context) error {
+		_, err := a.usecases.Webhook.DeliverDue(ctx)
+
+		return err
+	})
+
+	a.runPeriodic(ctx, wg, "reminders", a.config.Reminders.PollInterval, func(ctx context.Context) error {
+		_, err := a.usecases.Reminder.SendDue(ctx)
+
+		return err
+	})
+
+	a.runPeriodic(ctx, wg, "digest", a.config.Digest.PollInterval, func(ctx context.Context) error {
+		_, err := a.usecases.Digest.SendDue(ctx)
+
+		return err
+	})
+
+	if a.config.RollForward.Policy == configuration.RollForwardAdvance {
+		a.runDaily(ctx, wg, "roll-forward", a.rollForward, func(ctx context.Context) error {
+			_, err := a.usecases.TodoTask.RollForward(ctx)
+
+			return err
+		})
+	}
+
+	a.watchConfig(ctx, wg)
+
+	// Закрытие шины завершает открытые SSE-потоки, иначе они держат остановку сервера.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		a.events.Close()
+	}()
+
 	return errCh
 }
+
+// Close освобождает ресурсы приложения после остановки воркеров и серверов.
+func (a *App) Close(ctx context.Context) error {
+	if err := a.tracing.Shutdown(ctx); err != nil {
+		return fmt.Errorf("tracing.Shutdown error: %w", err)
+	}
+
+	return nil
+}
+
+// newNotifier возвращает отправку уведомлений по SMTP или, если почта не настроена,
+// запись уведомлений в журнал.
+func newNotifier(smtp *credentials.SMTP, timeout time.Duration) (usecases.INotifier, error) {
+	if smtp == nil {
+		slog.Warn("SMTP is not configured, notifications will only be logged and marked as failed")
+
+		return notifier.Log{}, nil
+	}
+
+	return notifier.NewSMTP(&notifier.SMTPDependencies{
+		Host:     smtp.Host,
+		Port:     smtp.Port,
+		Username: smtp.Username,
+		Password: smtp.Password,
+		From:     smtp.From,
+		Timeout:  timeout,
+	})
+}
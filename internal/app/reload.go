@@ -0,0 +1,173 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sater-151/todo-list/internal/configuration"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+	"github.com/sater-151/todo-list/internal/usecases"
+)
+
+// reloadable — поля настроек, которые применяются без перезапуска. Изменения
+// остальных полей только записываются в журнал.
+//
+// Таймауты и лимиты HTTPServer и порт Admin не меняются на ходу: http.Server читает
+// их без синхронизации, а запущенный сервер нельзя перенастроить без остановки
+// приёма соединений. Об их изменении watchConfig пишет отдельное предупреждение.
+// Интервалы опроса фоновых задач и часовые пояса задаются при их запуске.
+var reloadable = []string{
+	"Logger.Level",
+	"Webhooks.Timeout",
+	"Webhooks.MaxAttempts",
+	"Webhooks.DisableAfter",
+	"Reminders.Timeout",
+	"Reminders.MaxAttempts",
+	"Digest.Recipients",
+	"Digest.SendAt",
+	"Digest.Limit",
+	"Digest.MaxAttempts",
+}
+
+// watchConfig перечитывает файл настроек при его изменении и применяет изменяемые
+// на лету поля. Неверные настройки отклоняются, действующие остаются прежними.
+func (a *App) watchConfig(ctx context.Context, wg *sync.WaitGroup) {
+	if a.configPath == "" {
+		return
+	}
+
+	logger := a.logger.With(slog.String("worker", "config-reload"))
+	current := a.config
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		err := configuration.Watch(ctx, a.configPath, func(next *configuration.Configurations, err error) {
+			if err == nil {
+				next.Version = current.Version
+				err = a.applyConfig(next)
+			}
+
+			if err != nil {
+				logger.Error("configuration reload rejected, keeping the previous one",
+					slog.String("path", a.configPath), slog.String("error", err.Error()))
+
+				return
+			}
+
+			applied, restart := diffConfig(current, next)
+			current = next
+
+			logger.Info("configuration reloaded",
+				slog.String("path", a.configPath), slog.Any("applied", applied))
+
+			if server := serverFields(restart); len(server) > 0 {
+				logger.Warn("HTTP server settings are applied only on start, restart to use them",
+					slog.Any("fields", server))
+			}
+
+			if len(restart) > 0 {
+				logger.Warn("configuration changes take effect after restart", slog.Any("fields", restart))
+			}
+		})
+		if err != nil {
+			logger.Error("configuration watcher stopped", slog.String("error", err.Error()))
+		}
+	}()
+}
+
+// applyConfig применяет изменяемые на лету поля next. Все настройки проверяются
+// до применения, чтобы не применить их частично.
+func (a *App) applyConfig(next *configuration.Configurations) error {
+	reminder := &usecases.ReminderSettings{
+		Timeout:     next.Reminders.Timeout,
+		MaxAttempts: next.Reminders.MaxAttempts,
+	}
+
+	if err := validate.Struct(reminder); err != nil {
+		return errorspkg.NewValidationError("app.applyConfig", reminder, err)
+	}
+
+	webhook := &usecases.WebhookSettings{
+		Timeout:      next.Webhooks.Timeout,
+		MaxAttempts:  next.Webhooks.MaxAttempts,
+		DisableAfter: next.Webhooks.DisableAfter,
+	}
+
+	if err := validate.Struct(webhook); err != nil {
+		return errorspkg.NewValidationError("app.applyConfig", webhook, err)
+	}
+
+	err := a.usecases.Digest.SetSettings(&usecases.DigestSettings{
+		SendAt:      next.Digest.SendAt,
+		Recipients:  next.Digest.Recipients,
+		Limit:       next.Digest.Limit,
+		Timeout:     next.Reminders.Timeout,
+		MaxAttempts: next.Digest.MaxAttempts,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := a.usecases.Reminder.SetSettings(reminder); err != nil {
+		return err
+	}
+
+	if err := a.usecases.Webhook.SetSettings(webhook); err != nil {
+		return err
+	}
+
+	a.logLevel.Set(next.Logger.Level)
+
+	return nil
+}
+
+// diffConfig возвращает изменившиеся поля вида Section.Field: применённые на лету
+// и требующие перезапуска.
+func diffConfig(prev, next *configuration.Configurations) (applied, restart []string) {
+	pv := reflect.ValueOf(prev).Elem()
+	nv := reflect.ValueOf(next).Elem()
+
+	for i := range pv.NumField() {
+		section := pv.Type().Field(i)
+
+		ps, ns := reflect.Indirect(pv.Field(i)), reflect.Indirect(nv.Field(i))
+		if ps.Kind() != reflect.Struct || ns.Kind() != reflect.Struct {
+			continue
+		}
+
+		for j := range ps.NumField() {
+			if reflect.DeepEqual(ps.Field(j).Interface(), ns.Field(j).Interface()) {
+				continue
+			}
+
+			name := section.Name + "." + ps.Type().Field(j).Name
+			if slices.Contains(reloadable, name) {
+				applied = append(applied, name)
+			} else {
+				restart = append(restart, name)
+			}
+		}
+	}
+
+	return applied, restart
+}
+
+// serverFields возвращает из fields настройки HTTP-серверов.
+func serverFields(fields []string) []string {
+	var server []string
+
+	for _, field := range fields {
+		if strings.HasPrefix(field, "HTTPServer.") || strings.HasPrefix(field, "Admin.") {
+			server = append(server, field)
+		}
+	}
+
+	return server
+}
@@ -13,6 +13,7 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib" // драйвер для postgres
 	"github.com/pressly/goose/v3"
 	"github.com/sater-151/todo-list/internal/credentials"
+	"github.com/sater-151/todo-list/internal/pkg/tracing"
 	"github.com/sater-151/todo-list/internal/repository"
 	"github.com/sater-151/todo-list/internal/repository/postgres"
 )
@@ -22,11 +23,26 @@ const (
 )
 
 type Repository struct {
+	Pool     *pgxpool.Pool
 	TodoTask repository.ITodoTask
+	View     repository.IView
+	Webhook  repository.IWebhook
+	Outbox   repository.IOutbox
+	Reminder repository.IReminder
+	Digest   repository.IDigest
+	Feed     repository.IFeed
+	Health   repository.IHealth
 }
 
 func NewRepo(ctx context.Context, c credentials.Postgres) (*Repository, error) {
-	pool, err := pgxpool.New(ctx, c.ConnString)
+	config, err := pgxpool.ParseConfig(c.ConnString)
+	if err != nil {
+		return nil, fmt.Errorf("parsing postgres connection string: %w", err)
+	}
+
+	config.ConnConfig.Tracer = tracing.QueryTracer{}
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("connecting to postgres: %w", err)
 	}
@@ -61,11 +77,69 @@ func initRepoRegistry(postgresConnect *pgxpool.Pool) (*Repository, error) {
 		return nil, err
 	}
 
+	viewRepo, err := postgres.NewViewRepo(postgresConnect)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookRepo, err := postgres.NewWebhookRepo(postgresConnect)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxRepo, err := postgres.NewOutboxRepo(postgresConnect)
+	if err != nil {
+		return nil, err
+	}
+
+	reminderRepo, err := postgres.NewReminderRepo(postgresConnect)
+	if err != nil {
+		return nil, err
+	}
+
+	digestRepo, err := postgres.NewDigestRepo(postgresConnect)
+	if err != nil {
+		return nil, err
+	}
+
+	feedRepo, err := postgres.NewFeedRepo(postgresConnect)
+	if err != nil {
+		return nil, err
+	}
+
+	healthRepo, err := postgres.NewHealthRepo(postgresConnect)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Repository{
+		Pool:     postgresConnect,
 		TodoTask: todoTaskRepo,
+		View:     viewRepo,
+		Webhook:  webhookRepo,
+		Outbox:   outboxRepo,
+		Reminder: reminderRepo,
+		Digest:   digestRepo,
+		Feed:     feedRepo,
+		Health:   healthRepo,
 	}, nil
 }
 
+// latestMigration возвращает номер последней миграции из migrationsPath.
+func latestMigration() (int64, error) {
+	migrations, err := goose.CollectMigrations(migrationsPath, 0, goose.MaxVersion)
+	if err != nil {
+		return 0, fmt.Errorf("cannot collect migrations: %w", err)
+	}
+
+	last, err := migrations.Last()
+	if err != nil {
+		return 0, fmt.Errorf("cannot find last migration: %w", err)
+	}
+
+	return last.Version, nil
+}
+
 func postgresURL(pool *pgxpool.Pool) string {
 	conf := pool.Config().ConnConfig
 
@@ -1,6 +1,11 @@
 package app
 
 import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/configuration"
 	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
 	"github.com/sater-151/todo-list/internal/pkg/validate"
 	"github.com/sater-151/todo-list/internal/usecases"
@@ -8,11 +13,23 @@ import (
 
 type (
 	UsecasesDependencies struct {
-		Repository *Repository `validate:"required"`
+		Repository    *Repository                   `validate:"required"`
+		Events        usecases.IEventSink           `validate:"required"`
+		Notifier      usecases.INotifier            `validate:"required"`
+		Metrics       usecases.ITaskMetrics         `validate:"required"`
+		Migration     int64                         `validate:"gt=0"`
+		Configuration *configuration.Configurations `validate:"required"`
 	}
 
 	Usecases struct {
 		TodoTask *usecases.TodoTask
+		View     *usecases.View
+		Webhook  *usecases.Webhook
+		Outbox   *usecases.Outbox
+		Reminder *usecases.Reminder
+		Digest   *usecases.Digest
+		Feed     *usecases.Feed
+		Health   *usecases.Health
 	}
 )
 
@@ -21,8 +38,94 @@ func NewUsecases(d *UsecasesDependencies) (*Usecases, error) {
 		return nil, errorspkg.NewValidationError("app.NewUsecases", d, err)
 	}
 
+	view, err := usecases.NewView(&usecases.ViewDependencies{
+		ViewRepo:     d.Repository.View,
+		TodoTaskRepo: d.Repository.TodoTask,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Таймаут запроса задаётся контекстом в Webhook.send и меняется без перезапуска,
+	// поэтому у клиента своего таймаута нет.
+	webhook, err := usecases.NewWebhook(&usecases.WebhookDependencies{
+		WebhookRepo:  d.Repository.Webhook,
+		Client:       &http.Client{},
+		Timeout:      d.Configuration.Webhooks.Timeout,
+		MaxAttempts:  d.Configuration.Webhooks.MaxAttempts,
+		DisableAfter: d.Configuration.Webhooks.DisableAfter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Вебхуки ставятся в очередь одним экземпляром, а шина SSE у каждого экземпляра своя.
+	outbox, err := usecases.NewOutbox(&usecases.OutboxDependencies{
+		OutboxRepo: d.Repository.Outbox,
+		Sinks:      []usecases.IEventSink{webhook},
+		Broadcast:  []usecases.IEventSink{d.Events},
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	todoTask, err := usecases.NewTodoTask(&usecases.TodoTaskDependencies{
 		TodoTaskRepo: d.Repository.TodoTask,
+		Outbox:       outbox,
+		Metrics:      d.Metrics,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(d.Configuration.Reminders.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("loading reminders timezone: %w", err)
+	}
+
+	reminder, err := usecases.NewReminder(&usecases.ReminderDependencies{
+		ReminderRepo: d.Repository.Reminder,
+		TodoTaskRepo: d.Repository.TodoTask,
+		Notifier:     d.Notifier,
+		Location:     location,
+		Timeout:      d.Configuration.Reminders.Timeout,
+		MaxAttempts:  d.Configuration.Reminders.MaxAttempts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	digestLocation, err := time.LoadLocation(d.Configuration.Digest.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("loading digest timezone: %w", err)
+	}
+
+	digest, err := usecases.NewDigest(&usecases.DigestDependencies{
+		DigestRepo:   d.Repository.Digest,
+		TodoTaskRepo: d.Repository.TodoTask,
+		Notifier:     d.Notifier,
+		Location:     digestLocation,
+		SendAt:       d.Configuration.Digest.SendAt,
+		Recipients:   d.Configuration.Digest.Recipients,
+		Limit:        d.Configuration.Digest.Limit,
+		Timeout:      d.Configuration.Reminders.Timeout,
+		MaxAttempts:  d.Configuration.Digest.MaxAttempts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := usecases.NewFeed(&usecases.FeedDependencies{
+		FeedRepo: d.Repository.Feed,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	health, err := usecases.NewHealth(&usecases.HealthDependencies{
+		HealthRepo: d.Repository.Health,
+		Migration:  d.Migration,
+		Timeout:    d.Configuration.Health.Timeout,
 	})
 	if err != nil {
 		return nil, err
@@ -30,5 +133,12 @@ func NewUsecases(d *UsecasesDependencies) (*Usecases, error) {
 
 	return &Usecases{
 		TodoTask: todoTask,
+		View:     view,
+		Webhook:  webhook,
+		Outbox:   outbox,
+		Reminder: reminder,
+		Digest:   digest,
+		Feed:     feed,
+		Health:   health,
 	}, nil
 }
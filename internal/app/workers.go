@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+)
+
+// runPeriodic запускает fn каждые interval до отмены ctx. Ошибки логируются
+// и не останавливают воркер.
+func (a *App) runPeriodic(
+	ctx context.Context, wg *sync.WaitGroup, name string, interval time.Duration, fn func(context.Context) error,
+) {
+	a.runTriggered(ctx, wg, name, interval, nil, fn)
+}
+
+// runTriggered как runPeriodic, но дополнительно запускает fn по сигналу из wake.
+func (a *App) runTriggered(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	name string,
+	interval time.Duration,
+	wake <-chan struct{},
+	fn func(context.Context) error,
+) {
+	logger := a.logger.With(slog.String("worker", name))
+	ctx = logctx.Into(ctx, logger)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := fn(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("worker run failed", slog.String("error", err.Error()))
+			}
+
+			select {
+			case <-ctx.Done():
+				logger.Info("worker stopped")
+
+				return
+			case <-ticker.C:
+			case <-wake:
+			}
+		}
+	}()
+}
+
+// dailySchedule — ежедневный запуск в заданное время по часам зоны location.
+type dailySchedule struct {
+	hour, minute int
+	location     *time.Location
+}
+
+// newDailySchedule разбирает время запуска at (ЧЧ:ММ) и зону timezone.
+func newDailySchedule(at, timezone string) (*dailySchedule, error) {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q: %w", at, err)
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("loading timezone: %w", err)
+	}
+
+	return &dailySchedule{hour: t.Hour(), minute: t.Minute(), location: location}, nil
+}
+
+// next возвращает ближайший момент запуска позже now. При переходе на летнее время
+// несуществующее время запуска сдвигается вперёд, как это делает time.Date.
+func (s *dailySchedule) next(now time.Time) time.Time {
+	local := now.In(s.location)
+
+	run := time.Date(local.Year(), local.Month(), local.Day(), s.hour, s.minute, 0, 0, s.location)
+	if !run.After(now) {
+		run = time.Date(local.Year(), local.Month(), local.Day()+1, s.hour, s.minute, 0, 0, s.location)
+	}
+
+	return run
+}
+
+// runDaily запускает fn по расписанию schedule до отмены ctx. Время запуска не зависит
+// от момента старта, поэтому у всех экземпляров совпадает. Ошибки логируются
+// и не останавливают воркер.
+func (a *App) runDaily(
+	ctx context.Context, wg *sync.WaitGroup, name string, schedule *dailySchedule, fn func(context.Context) error,
+) {
+	logger := a.logger.With(slog.String("worker", name))
+	ctx = logctx.Into(ctx, logger)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			next := schedule.next(time.Now())
+			logger.Debug("worker scheduled", slog.Time("next_run", next))
+
+			timer := time.NewTimer(time.Until(next))
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				logger.Info("worker stopped")
+
+				return
+			case <-timer.C:
+			}
+
+			if err := fn(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("worker run failed", slog.String("error", err.Error()))
+			}
+		}
+	}()
+}
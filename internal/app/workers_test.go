@@ -0,0 +1,84 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailyScheduleNext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		at       string
+		timezone string
+		now      time.Time
+		want     time.Time
+	}{
+		{
+			name:     "later today",
+			at:       "03:00",
+			timezone: "UTC",
+			now:      time.Date(2026, time.October, 19, 2, 59, 30, 0, time.UTC),
+			want:     time.Date(2026, time.October, 19, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "exactly at run time",
+			at:       "03:00",
+			timezone: "UTC",
+			now:      time.Date(2026, time.October, 19, 3, 0, 0, 0, time.UTC),
+			want:     time.Date(2026, time.October, 20, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "tomorrow",
+			at:       "03:00",
+			timezone: "UTC",
+			now:      time.Date(2026, time.December, 31, 12, 0, 0, 0, time.UTC),
+			want:     time.Date(2027, time.January, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			// В Токио уже 20 октября, 02:00.
+			name:     "day of the schedule timezone",
+			at:       "03:00",
+			timezone: "Asia/Tokyo",
+			now:      time.Date(2026, time.October, 19, 17, 0, 0, 0, time.UTC),
+			want:     time.Date(2026, time.October, 19, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "after switching to winter time",
+			at:       "03:00",
+			timezone: "Europe/Berlin",
+			now:      time.Date(2026, time.October, 24, 12, 0, 0, 0, time.UTC),
+			want:     time.Date(2026, time.October, 25, 2, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := newDailySchedule(tt.at, tt.timezone)
+			if err != nil {
+				t.Fatalf("newDailySchedule(%q, %q): %v", tt.at, tt.timezone, err)
+			}
+
+			if got := s.next(tt.now); !got.Equal(tt.want) {
+				t.Errorf("next(%s) = %s, want %s", tt.now, got.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDailyScheduleInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct{ at, timezone string }{
+		{at: "25:00", timezone: "UTC"},
+		{at: "3am", timezone: "UTC"},
+		{at: "03:00", timezone: "Mars/Olympus"},
+	} {
+		if _, err := newDailySchedule(tt.at, tt.timezone); err == nil {
+			t.Errorf("newDailySchedule(%q, %q) accepted an invalid schedule", tt.at, tt.timezone)
+		}
+	}
+}
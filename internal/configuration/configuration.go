@@ -5,20 +5,32 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/sater-151/todo-list/internal/pkg/configloader"
 	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
 	"github.com/sater-151/todo-list/internal/pkg/validate"
-	"github.com/spf13/viper"
 )
 
 const (
-	_defaultConfigurationsPath = "configuration.yaml"
+	DefaultPath = "configuration.yaml"
+	EnvPrefix   = "TODO"
+
+	RollForwardKeep    = "keep"
+	RollForwardAdvance = "advance"
 )
 
 type (
 	Configurations struct {
-		Logger     *Logger     `mapstructure:"Logger" validate:"required"`
-		HTTPServer *HTTPServer `mapstructure:"HTTPServer" validate:"required"`
-		Version    string      `validate:"-"`
+		Logger      *Logger      `mapstructure:"Logger" validate:"required"`
+		HTTPServer  *HTTPServer  `mapstructure:"HTTPServer" validate:"required"`
+		Admin       *Admin       `mapstructure:"Admin" validate:"required"`
+		Health      *Health      `mapstructure:"Health" validate:"required"`
+		Tracing     *Tracing     `mapstructure:"Tracing" validate:"required"`
+		Webhooks    *Webhooks    `mapstructure:"Webhooks" validate:"required"`
+		Outbox      *Outbox      `mapstructure:"Outbox" validate:"required"`
+		Reminders   *Reminders   `mapstructure:"Reminders" validate:"required"`
+		Digest      *Digest      `mapstructure:"Digest" validate:"required"`
+		RollForward *RollForward `mapstructure:"RollForward" validate:"required"`
+		Version     string       `validate:"-"`
 	}
 
 	HTTPServer struct {
@@ -31,22 +43,78 @@ type (
 		MaxHeaderBytes    int           `mapstructure:"MaxHeaderBytes" validate:"gt=0"`
 	}
 
-	Logger struct {
-		Level slog.Level `mapstructure:"Level" validate:"min=-4,max=8"`
+	// Admin — служебный HTTP-сервер с метриками. Остальные настройки берутся из HTTPServer.
+	Admin struct {
+		Port string `mapstructure:"Port" validate:"required,min=1"`
+	}
+
+	// Health — проверки готовности. Timeout ограничивает проверки одного запроса
+	// /readyz, DrainDelay — время между переходом в неготовность и остановкой сервера.
+	Health struct {
+		Timeout    time.Duration `mapstructure:"Timeout" validate:"required"`
+		DrainDelay time.Duration `mapstructure:"DrainDelay" validate:"gte=0"`
+	}
+
+	// Tracing — экспорт трассировок OpenTelemetry: none, stdout или otlp (OTLP/HTTP
+	// на Endpoint). SampleRatio — доля трассировок, начатых в сервисе.
+	Tracing struct {
+		Exporter    string  `mapstructure:"Exporter" validate:"oneof=none stdout otlp"`
+		Endpoint    string  `mapstructure:"Endpoint" validate:"required_if=Exporter otlp"`
+		Insecure    bool    `mapstructure:"Insecure"`
+		SampleRatio float64 `mapstructure:"SampleRatio" validate:"gte=0,lte=1"`
+	}
+
+	Webhooks struct {
+		PollInterval time.Duration `mapstructure:"PollInterval" validate:"required"`
+		Timeout      time.Duration `mapstructure:"Timeout" validate:"required"`
+		MaxAttempts  int           `mapstructure:"MaxAttempts" validate:"gt=0"`
+		DisableAfter int           `mapstructure:"DisableAfter" validate:"gt=0"`
 	}
-)
 
-func NewConfig() (*Configurations, error) {
-	vp := viper.New()
+	Outbox struct {
+		PollInterval time.Duration `mapstructure:"PollInterval" validate:"required"`
+	}
+
+	// Reminders — настройки отправки напоминаний. Timezone — имя зоны IANA,
+	// в которой считается время срабатывания.
+	Reminders struct {
+		PollInterval time.Duration `mapstructure:"PollInterval" validate:"required"`
+		Timezone     string        `mapstructure:"Timezone" validate:"required,timezone,ne=Local"`
+		Timeout      time.Duration `mapstructure:"Timeout" validate:"required"`
+		MaxAttempts  int           `mapstructure:"MaxAttempts" validate:"gt=0"`
+	}
+
+	// Digest — ежедневная сводка задач. Сводка уходит каждому из Recipients
+	// один раз в день после SendAt (ЧЧ:ММ в зоне Timezone). Без получателей не отправляется.
+	Digest struct {
+		Recipients   []string      `mapstructure:"Recipients" validate:"dive,email"`
+		SendAt       string        `mapstructure:"SendAt" validate:"required,datetime=15:04"`
+		Timezone     string        `mapstructure:"Timezone" validate:"required,timezone,ne=Local"`
+		PollInterval time.Duration `mapstructure:"PollInterval" validate:"required"`
+		Limit        int           `mapstructure:"Limit" validate:"gt=0"`
+		MaxAttempts  int           `mapstructure:"MaxAttempts" validate:"gt=0"`
+	}
 
-	vp.SetConfigFile(_defaultConfigurationsPath)
-	if err := vp.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("reading configuration: %w", err)
+	// RollForward — перенос просроченных повторяющихся задач. При Policy keep задачи
+	// остаются просроченными, при advance переносятся на ближайшую дату каждый день
+	// в RunAt (ЧЧ:ММ в зоне Timezone).
+	RollForward struct {
+		Policy   string `mapstructure:"Policy" validate:"oneof=keep advance"`
+		RunAt    string `mapstructure:"RunAt" validate:"required,datetime=15:04"`
+		Timezone string `mapstructure:"Timezone" validate:"required,timezone,ne=Local"`
 	}
 
+	Logger struct {
+		Level slog.Level `mapstructure:"Level" validate:"min=-4,max=8"`
+	}
+)
+
+// NewConfig читает настройки из файла path с переопределением переменными
+// окружения TODO_*; порядок приоритета описан в configloader.Load.
+func NewConfig(path string) (*Configurations, error) {
 	var configurations Configurations
-	if err := vp.Unmarshal(&configurations); err != nil {
-		return nil, fmt.Errorf("unmarshaling configuration: %w", err)
+	if err := configloader.Load(path, EnvPrefix, &configurations); err != nil {
+		return nil, fmt.Errorf("loading configuration: %w", err)
 	}
 
 	if err := validate.Struct(configurations); err != nil {
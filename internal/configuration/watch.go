@@ -0,0 +1,65 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce — пауза после последнего события: редакторы и Kubernetes
+// заменяют файл несколькими операциями подряд.
+const watchDebounce = 200 * time.Millisecond
+
+// Watch следит за файлом path и после каждого изменения заново читает настройки
+// через NewConfig, передавая в fn результат или ошибку чтения и проверки.
+// Следит за каталогом файла, чтобы пережить замену файла переименованием и
+// подмену символической ссылки. Возвращается после отмены ctx.
+func Watch(ctx context.Context, path string, fn func(*Configurations, error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating configuration watcher: %w", err)
+	}
+
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("watching %s: %w", path, err)
+	}
+
+	target := filepath.Clean(path)
+
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			// В смонтированном ConfigMap меняется ссылка ..data, а не сам файл.
+			if filepath.Clean(event.Name) == target || filepath.Base(event.Name) == "..data" {
+				timer.Reset(watchDebounce)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			fn(nil, fmt.Errorf("watching %s: %w", path, err))
+
+		case <-timer.C:
+			fn(NewConfig(path))
+		}
+	}
+}
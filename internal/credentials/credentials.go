@@ -3,41 +3,47 @@ package credentials
 import (
 	"fmt"
 
+	"github.com/sater-151/todo-list/internal/pkg/configloader"
 	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
 	"github.com/sater-151/todo-list/internal/pkg/validate"
-	"github.com/spf13/viper"
 )
 
 const (
-	_defaultCredentialPath = "credentials.yaml"
+	DefaultPath = "credentials.yaml"
+	EnvPrefix   = "TODO"
 )
 
 type (
 	Credentials struct {
 		Postgres *Postgres `mapstructure:"Postgres" validate:"required"`
 		Data     *Data     `mapstructure:"Data" validate:"required"`
+		SMTP     *SMTP     `mapstructure:"SMTP" validate:"omitempty"`
 	}
 
 	Postgres struct {
 		ConnString string `mapstructure:"ConnString" validate:"required"`
 	}
 
+	// SMTP — почтовый сервер для уведомлений. Без него уведомления только пишутся в журнал.
+	SMTP struct {
+		Host     string `mapstructure:"Host" validate:"required"`
+		Port     int    `mapstructure:"Port" validate:"gt=0,lte=65535"`
+		Username string `mapstructure:"Username"`
+		Password string `mapstructure:"Password"`
+		From     string `mapstructure:"From" validate:"required,email"`
+	}
+
 	Data struct {
 		Password string `mapstructure:"Password" validate:"required"`
 	}
 )
 
-func NewCredentials() (*Credentials, error) {
-	vp := viper.New()
-
-	vp.SetConfigFile(_defaultCredentialPath)
-	if err := vp.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("reading credentials: %w", err)
-	}
-
+// NewCredentials читает секреты из файла path с переопределением переменными
+// окружения TODO_* и файлами из TODO_*_FILE; порядок приоритета описан в configloader.Load.
+func NewCredentials(path string) (*Credentials, error) {
 	var credentials Credentials
-	if err := vp.Unmarshal(&credentials); err != nil {
-		return nil, fmt.Errorf("unmarshal credentials: %w", err)
+	if err := configloader.Load(path, EnvPrefix, &credentials); err != nil {
+		return nil, fmt.Errorf("loading credentials: %w", err)
 	}
 
 	if err := validate.Struct(credentials); err != nil {
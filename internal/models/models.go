@@ -1,21 +1,232 @@
 package models
 
+import "time"
+
 type Task struct {
-	ID      string `json:"id"`
-	Date    string `json:"date"`
-	Title   string `json:"title"`
-	Comment string `json:"comment"`
-	Repeat  string `json:"repeat"`
+	ID                string `json:"id"`
+	Date              string `json:"date"`
+	Title             string `json:"title"`
+	Comment           string `json:"comment"`
+	Repeat            string `json:"repeat"`
+	RepeatDescription string `json:"repeat_description,omitempty"`
+	Snippet           string `json:"snippet,omitempty"`
+	SnoozedFrom       string `json:"snoozed_from,omitempty"`
 }
 
 type SelectConfig struct {
-	ID       string
-	Search   string
-	Date     string
-	Limit    string
-	Sort     string
-	TypeSort string
-	Table    string
+	ID         string
+	Search     string
+	Date       string
+	DateFrom   string
+	DateTo     string
+	DateBefore string
+	Repeating  *bool
+	HasComment *bool
+	Tags       []string
+	Limit      string
+	Offset     string
+	Sort       string
+	TypeSort   string
+	Table      string
+}
+
+type TaskFilter struct {
+	Search     string   `json:"search,omitempty" validate:"max=256"`
+	Tags       []string `json:"tags,omitempty" validate:"max=16,dive,min=1,max=64"`
+	Period     string   `json:"period,omitempty" validate:"omitempty,oneof=today tomorrow today_and_overdue this_week next_week this_month next_7_days"` //nolint:lll
+	From       string   `json:"from,omitempty" validate:"omitempty,datetime=20060102"`
+	To         string   `json:"to,omitempty" validate:"omitempty,datetime=20060102"`
+	Overdue    bool     `json:"overdue,omitempty"`
+	Repeating  *bool    `json:"repeating,omitempty"`
+	HasComment *bool    `json:"has_comment,omitempty"`
+	Sort       string   `json:"sort,omitempty" validate:"omitempty,oneof=date title"`
+	Order      string   `json:"order,omitempty" validate:"omitempty,oneof=asc desc"`
+}
+
+type View struct {
+	ID     string     `json:"id"`
+	Name   string     `json:"name" validate:"required,max=128"`
+	Filter TaskFilter `json:"filter"`
+}
+
+type ListView struct {
+	Views []View `json:"views"`
+}
+
+// ViewTasks — страница задач представления. NextOffset — смещение следующей
+// страницы, пусто на последней.
+type ViewTasks struct {
+	Tasks      []Task `json:"tasks"`
+	NextOffset int    `json:"next_offset,omitempty"`
+}
+
+type Webhook struct {
+	ID             string   `json:"id"`
+	URL            string   `json:"url" validate:"required,http_url,max=2048"`
+	Events         []string `json:"events" validate:"dive,oneof=created updated done advanced deleted"`
+	Secret         string   `json:"secret,omitempty" validate:"omitempty,min=16,max=256"`
+	Enabled        bool     `json:"enabled"`
+	Failures       int      `json:"failures"`
+	DisabledReason string   `json:"disabled_reason,omitempty"`
+}
+
+type ListWebhook struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+type WebhookDelivery struct {
+	ID            string     `json:"id"`
+	WebhookID     string     `json:"webhook_id"`
+	EventID       string     `json:"event_id"`
+	EventType     string     `json:"event_type"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	ResponseCode  int        `json:"response_code,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+
+	Payload []byte `json:"-"`
+	URL     string `json:"-"`
+	Secret  string `json:"-"`
+}
+
+type ListWebhookDelivery struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
+
+const (
+	ReminderPending = "pending"
+	ReminderSent    = "sent"
+	ReminderFailed  = "failed"
+)
+
+// Reminder — напоминание о задаче за DaysBefore дней до её даты в Time (ЧЧ:ММ).
+// Status и Attempts относятся к дате задачи Occurrence.
+type Reminder struct {
+	ID         string     `json:"id"`
+	TaskID     string     `json:"task_id" validate:"required,uuid"`
+	DaysBefore int        `json:"days_before" validate:"min=0,max=365"`
+	Time       string     `json:"time" validate:"required,datetime=15:04"`
+	Email      string     `json:"email" validate:"required,email,max=254"`
+	Occurrence string     `json:"occurrence,omitempty"`
+	Status     string     `json:"status"`
+	Attempts   int        `json:"attempts"`
+	LastError  string     `json:"last_error,omitempty"`
+	SentAt     *time.Time `json:"sent_at,omitempty"`
+
+	NextAttemptAt *time.Time `json:"-"`
+	FireAt        time.Time  `json:"-"`
+	Task          *Task      `json:"-"`
+}
+
+type ListReminder struct {
+	Reminders []Reminder `json:"reminders"`
+}
+
+const (
+	DigestPending = "pending"
+	DigestSent    = "sent"
+	DigestSkipped = "skipped"
+	DigestFailed  = "failed"
+)
+
+// Digest — отправка ежедневной сводки получателю Recipient за день Day (ГГГГММДД).
+type Digest struct {
+	Recipient string
+	Day       string
+	Status    string
+	Attempts  int
+	LastError string
+	SentAt    *time.Time
+}
+
+// Notification — сообщение пользователю. HTML необязателен.
+type Notification struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type CalendarRange struct {
+	From string `validate:"required,datetime=20060102"`
+	To   string `validate:"required,datetime=20060102"`
+}
+
+type Occurrence struct {
+	SeriesID string `json:"series_id"`
+	Date     string `json:"date"`
+	Title    string `json:"title"`
+	Comment  string `json:"comment"`
+	Repeat   string `json:"repeat"`
+}
+
+type CalendarDay struct {
+	Date        string       `json:"date"`
+	Occurrences []Occurrence `json:"occurrences"`
+}
+
+type Calendar struct {
+	From string        `json:"from"`
+	To   string        `json:"to"`
+	Days []CalendarDay `json:"days"`
+}
+
+type QuickTask struct {
+	Text string `json:"text"`
+}
+
+type ImportProblem struct {
+	UID    string `json:"uid,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Reason string `json:"reason"`
+}
+
+const (
+	TaskEventCreated  = "created"
+	TaskEventUpdated  = "updated"
+	TaskEventDone     = "done"
+	TaskEventAdvanced = "advanced"
+	TaskEventDeleted  = "deleted"
+	TaskEventReset    = "reset"
+)
+
+type TaskEvent struct {
+	ID     string    `json:"id"`
+	Type   string    `json:"type"`
+	TaskID string    `json:"task_id,omitempty"`
+	Task   *Task     `json:"task,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+const (
+	ImportModeMerge   = "merge"
+	ImportModeReplace = "replace"
+)
+
+// ImportOptions: Restore — восстановление из выгрузки, даты задач сохраняются как есть,
+// без переноса прошедших дат.
+type ImportOptions struct {
+	Mode    string `validate:"omitempty,oneof=merge replace"`
+	DryRun  bool
+	Restore bool
+}
+
+type ImportResult struct {
+	Mode     string          `json:"mode"`
+	DryRun   bool            `json:"dry_run"`
+	Imported int             `json:"imported"`
+	Tasks    []Task          `json:"tasks"`
+	Problems []ImportProblem `json:"problems,omitempty"`
 }
 
 type ID struct {
@@ -34,6 +245,26 @@ type ListTask struct {
 	Tasks []Task `json:"tasks"`
 }
 
+// MissedTask — дата повторяющейся задачи, пропущенная при переносе задачи вперёд.
+type MissedTask struct {
+	TaskID    string    `json:"task_id"`
+	Title     string    `json:"title"`
+	Date      string    `json:"date"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListMissedTask struct {
+	Missed []MissedTask `json:"missed"`
+}
+
+// Snooze — перенос задачи на дату Until (ГГГГММДД) или на срок For вида 2d, 1w, 1m.
+// Задаётся ровно одно из полей.
+type Snooze struct {
+	ID    string `validate:"required,uuid"`
+	Until string `validate:"omitempty,datetime=20060102"`
+	For   string `validate:"omitempty,max=8"`
+}
+
 type PasswordJS struct {
 	Pass string `json:"password"`
 }
@@ -41,3 +272,21 @@ type PasswordJS struct {
 type JWTToken struct {
 	Token string `json:"token"`
 }
+
+const (
+	HealthOK       = "ok"
+	HealthFailed   = "failed"
+	HealthDraining = "draining"
+)
+
+// HealthCheck — результат одной проверки готовности.
+type HealthCheck struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+type Readiness struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
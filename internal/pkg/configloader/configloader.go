@@ -0,0 +1,125 @@
+package configloader
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// fileSuffix — суффикс переменной с путём к файлу секрета (Docker secrets,
+// тома с секретами Kubernetes).
+const fileSuffix = "_FILE"
+
+// Load читает YAML-файл path в out и накладывает поверх него переменные окружения.
+// Пустой path — только переменные окружения.
+//
+// Приоритет, от высшего к низшему:
+//  1. содержимое файла из переменной <ИМЯ>_FILE;
+//  2. переменная <ИМЯ>;
+//  3. значение из файла path.
+//
+// Имя переменной — prefix и путь к полю по тегам mapstructure через "_" в верхнем
+// регистре: поле Postgres.ConnString при префиксе TODO читается из TODO_POSTGRES_CONNSTRING.
+func Load(path, prefix string, out any) error {
+	vp, err := New(path, prefix, out)
+	if err != nil {
+		return err
+	}
+
+	if err := vp.Unmarshal(out); err != nil {
+		return fmt.Errorf("unmarshaling %s: %w", source(path), err)
+	}
+
+	return nil
+}
+
+// New возвращает viper с прочитанным файлом path и привязанными переменными
+// окружения для всех полей out, как описано в Load.
+func New(path, prefix string, out any) (*viper.Viper, error) {
+	vp := viper.New()
+
+	if path != "" {
+		vp.SetConfigFile(path)
+		if err := vp.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+
+	for _, key := range fieldKeys(reflect.TypeOf(out), "") {
+		env := envName(prefix, key)
+
+		if err := vp.BindEnv(key, env); err != nil {
+			return nil, fmt.Errorf("binding %s: %w", env, err)
+		}
+
+		file, ok := os.LookupEnv(env + fileSuffix)
+		if !ok {
+			continue
+		}
+
+		secret, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s%s: %w", env, fileSuffix, err)
+		}
+
+		// Set имеет в viper наивысший приоритет. Перевод строки в конце файла
+		// секрета почти всегда случаен.
+		vp.Set(key, strings.TrimRight(string(secret), "\r\n"))
+	}
+
+	return vp, nil
+}
+
+// envName возвращает имя переменной окружения для ключа viper вида Section.Field.
+func envName(prefix, key string) string {
+	return strings.ToUpper(prefix + "_" + strings.ReplaceAll(key, ".", "_"))
+}
+
+// fieldKeys возвращает ключи viper всех конечных полей структуры t с тегом mapstructure.
+func fieldKeys(t reflect.Type, parent string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var keys []string
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name := field.Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		if parent != "" {
+			name = parent + "." + name
+		}
+
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}) {
+			keys = append(keys, fieldKeys(ft, name)...)
+
+			continue
+		}
+
+		keys = append(keys, name)
+	}
+
+	return keys
+}
+
+func source(path string) string {
+	if path == "" {
+		return "environment"
+	}
+
+	return path
+}
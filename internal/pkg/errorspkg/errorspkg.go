@@ -8,6 +8,7 @@ import (
 
 var (
 	ErrBadRequest    = errors.New("bad request")
+	ErrNotFound      = errors.New("not found")
 	ErrInternalError = errors.New("internal error")
 )
 
@@ -76,3 +77,21 @@ func NewStrconvError(method, convertedValue string, err error) error {
 		ErrorMsg:       err,
 	}
 }
+
+type RepeatParseError struct {
+	Repeat   string
+	Position int
+	Reason   string
+}
+
+func (e RepeatParseError) Error() string {
+	return fmt.Sprintf("invalid repeat [%s] at position %d: %s", e.Repeat, e.Position, e.Reason)
+}
+
+func NewRepeatParseError(repeat string, position int, reason string) error {
+	return RepeatParseError{
+		Repeat:   repeat,
+		Position: position,
+		Reason:   reason,
+	}
+}
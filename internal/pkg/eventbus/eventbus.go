@@ -0,0 +1,174 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+)
+
+const (
+	DefaultHistorySize = 1000
+
+	subscriptionBuffer = 64
+)
+
+// Bus — шина событий задач внутри процесса. Последние события хранятся
+// в кольцевом буфере, чтобы переподключившийся клиент мог получить пропущенное.
+// ID события имеет вид "<epoch>-<seq>": epoch меняется при перезапуске,
+// поэтому ID от прошлого запуска не спутать с текущими.
+type Bus struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	history []models.TaskEvent
+	start   int
+	subs    map[*Subscription]struct{}
+	closed  bool
+}
+
+type Subscription struct {
+	C <-chan models.TaskEvent
+
+	ch  chan models.TaskEvent
+	bus *Bus
+}
+
+func New(historySize int) *Bus {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+
+	return &Bus{
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		history: make([]models.TaskEvent, 0, historySize),
+		subs:    make(map[*Subscription]struct{}),
+	}
+}
+
+// HandleEvent рассылает событие из outbox подписчикам. Событию присваивается
+// собственный ID шины, по которому клиенты досылают пропущенное.
+func (b *Bus) HandleEvent(_ context.Context, event models.TaskEvent) error {
+	task := event.Task
+	if task == nil {
+		task = &models.Task{ID: event.TaskID}
+	}
+
+	b.Publish(event.Type, task)
+
+	return nil
+}
+
+// Publish рассылает событие подписчикам. Подписчик, который не успевает читать,
+// отключается: он переподключится и дочитает события из истории.
+func (b *Bus) Publish(eventType string, task *models.Task) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.seq++
+	event := models.TaskEvent{
+		ID:     fmt.Sprintf("%s-%d", b.epoch, b.seq),
+		Type:   eventType,
+		TaskID: task.ID,
+		Time:   time.Now().UTC(),
+	}
+
+	switch eventType {
+	case models.TaskEventCreated, models.TaskEventUpdated, models.TaskEventDone, models.TaskEventAdvanced:
+		t := *task
+		event.Task = &t
+	}
+
+	if len(b.history) < cap(b.history) {
+		b.history = append(b.history, event)
+	} else {
+		b.history[b.start] = event
+		b.start = (b.start + 1) % len(b.history)
+	}
+
+	for sub := range b.subs {
+		select {
+		case sub.ch <- event:
+		default:
+			slog.Warn("eventbus: slow subscriber dropped")
+			b.remove(sub)
+		}
+	}
+}
+
+// Subscribe подписывает на новые события и возвращает события после lastEventID.
+// Если часть событий после lastEventID уже вытеснена из истории или ID от прошлого
+// запуска, complete = false и клиенту нужно перечитать задачи целиком.
+func (b *Bus) Subscribe(lastEventID string) (sub *Subscription, backlog []models.TaskEvent, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan models.TaskEvent, subscriptionBuffer)
+	sub = &Subscription{C: ch, ch: ch, bus: b}
+
+	if b.closed {
+		close(ch)
+
+		return sub, nil, true
+	}
+
+	b.subs[sub] = struct{}{}
+
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+
+	epoch, seqStr, _ := strings.Cut(lastEventID, "-")
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil || epoch != b.epoch || seq > b.seq {
+		return sub, nil, false
+	}
+
+	oldest := b.seq - uint64(len(b.history)) + 1
+	if seq+1 < oldest {
+		return sub, nil, false
+	}
+
+	for i := range b.history {
+		event := b.history[(b.start+i)%len(b.history)]
+		if oldest+uint64(i) > seq {
+			backlog = append(backlog, event)
+		}
+	}
+
+	return sub, backlog, true
+}
+
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.remove(s)
+}
+
+// Close отключает всех подписчиков, например при остановке сервиса.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subs {
+		b.remove(sub)
+	}
+}
+
+func (b *Bus) remove(sub *Subscription) {
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
@@ -0,0 +1,29 @@
+package logctx
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LevelHandler отбрасывает записи ниже level. С slog.LevelVar уровень можно менять
+// на ходу, даже если обработчик h этого не умеет.
+type LevelHandler struct {
+	slog.Handler
+	level slog.Leveler
+}
+
+func NewLevelHandler(h slog.Handler, level slog.Leveler) *LevelHandler {
+	return &LevelHandler{Handler: h, level: level}
+}
+
+func (h *LevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.Handler.Enabled(ctx, level)
+}
+
+func (h *LevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LevelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *LevelHandler) WithGroup(name string) slog.Handler {
+	return &LevelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
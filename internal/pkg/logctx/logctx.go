@@ -0,0 +1,46 @@
+package logctx
+
+import (
+	"context"
+	"log/slog"
+)
+
+type ctxKey struct{}
+
+// Into возвращает контекст, записи из которого уходят в logger.
+func Into(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// From возвращает журнал из контекста или slog.Default(), если его нет.
+func From(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
+
+// With добавляет поля ко всем последующим записям из контекста.
+func With(ctx context.Context, args ...any) context.Context {
+	return Into(ctx, From(ctx).With(args...))
+}
+
+// Функции ниже пишут в журнал контекста и передают ctx обработчику, чтобы
+// к записи добавились trace_id и span_id.
+
+func Debug(ctx context.Context, msg string, args ...any) {
+	From(ctx).DebugContext(ctx, msg, args...)
+}
+
+func Info(ctx context.Context, msg string, args ...any) {
+	From(ctx).InfoContext(ctx, msg, args...)
+}
+
+func Warn(ctx context.Context, msg string, args ...any) {
+	From(ctx).WarnContext(ctx, msg, args...)
+}
+
+func Error(ctx context.Context, msg string, args ...any) {
+	From(ctx).ErrorContext(ctx, msg, args...)
+}
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+const namespace = "todo"
+
+type (
+	Dependencies struct {
+		Version string        `validate:"required"`
+		Pool    *pgxpool.Pool `validate:"required"`
+	}
+
+	// Metrics — реестр метрик сервиса. Все метрики регистрируются в собственном
+	// реестре, а не в глобальном prometheus.DefaultRegisterer.
+	Metrics struct {
+		registry         *prometheus.Registry
+		httpRequests     *prometheus.CounterVec
+		httpDuration     *prometheus.HistogramVec
+		taskOperations   *prometheus.CounterVec
+		nextDateFailures prometheus.Counter
+	}
+)
+
+func New(d *Dependencies) (*Metrics, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("metrics.New", d, err)
+	}
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by method, chi route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method, chi route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		taskOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "tasks",
+			Name:      "operations_total",
+			Help:      "Completed task operations by type.",
+		}, []string{"operation"}),
+		nextDateFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "tasks",
+			Name:      "next_date_failures_total",
+			Help:      "Failures to compute the next date of a recurring task.",
+		}),
+	}
+
+	buildInfo := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "build_info",
+		Help:        "Build information; the value is always 1.",
+		ConstLabels: prometheus.Labels{"version": d.Version, "goversion": runtime.Version()},
+	})
+	buildInfo.Set(1)
+
+	m.registry.MustRegister(
+		m.httpRequests,
+		m.httpDuration,
+		m.taskOperations,
+		m.nextDateFailures,
+		buildInfo,
+		newPoolCollector(d.Pool),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m, nil
+}
+
+// Handler отдаёт метрики в формате Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware считает запросы и их длительность. Маршрут берётся из шаблона chi,
+// чтобы параметры пути не порождали новые серии; запросы без маршрута
+// учитываются как "unmatched".
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	fn := func(res http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(res, req.ProtoMajor)
+
+		next.ServeHTTP(ww, req)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(req.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		labels := prometheus.Labels{"method": req.Method, "route": route, "status": strconv.Itoa(status)}
+		m.httpRequests.With(labels).Inc()
+		m.httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// CountTaskOperation учитывает n выполненных операций с задачами.
+func (m *Metrics) CountTaskOperation(operation string, n int) {
+	m.taskOperations.WithLabelValues(operation).Add(float64(n))
+}
+
+func (m *Metrics) NextDateFailed() {
+	m.nextDateFailures.Inc()
+}
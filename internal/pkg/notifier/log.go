@@ -0,0 +1,24 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+)
+
+// ErrNotDelivered — уведомление только записано в журнал, потому что отправка
+// почты не настроена.
+var ErrNotDelivered = errors.New("notification not delivered: SMTP is not configured")
+
+// Log пишет уведомления в журнал. Используется, когда отправка почты не настроена.
+// Notify возвращает ErrNotDelivered, чтобы уведомление не считалось отправленным.
+type Log struct{}
+
+func (Log) Notify(ctx context.Context, n *models.Notification) error {
+	logctx.Info(ctx, "notification", slog.String("to", n.To), slog.String("subject", n.Subject))
+
+	return ErrNotDelivered
+}
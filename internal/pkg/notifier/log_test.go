@@ -0,0 +1,19 @@
+package notifier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/notifier"
+)
+
+func TestLogNotifyNotDelivered(t *testing.T) {
+	t.Parallel()
+
+	err := notifier.Log{}.Notify(context.Background(), &models.Notification{To: "user@example.com", Subject: "Report"})
+	if !errors.Is(err, notifier.ErrNotDelivered) {
+		t.Fatalf("Notify() error = %v, want %v", err, notifier.ErrNotDelivered)
+	}
+}
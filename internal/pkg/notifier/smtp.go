@@ -0,0 +1,195 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+type (
+	SMTPDependencies struct {
+		Host     string `validate:"required"`
+		Port     int    `validate:"gt=0,lte=65535"`
+		Username string
+		Password string
+		From     string        `validate:"required,email"`
+		Timeout  time.Duration `validate:"gt=0"`
+	}
+
+	// SMTP отправляет уведомления письмами. Если сервер поддерживает STARTTLS,
+	// соединение шифруется; авторизация выполняется, только если задан Username.
+	SMTP struct {
+		addr     string
+		host     string
+		username string
+		password string
+		from     string
+		timeout  time.Duration
+	}
+)
+
+func NewSMTP(d *SMTPDependencies) (*SMTP, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("notifier.NewSMTP", d, err)
+	}
+
+	return &SMTP{
+		addr:     net.JoinHostPort(d.Host, strconv.Itoa(d.Port)),
+		host:     d.Host,
+		username: d.Username,
+		password: d.Password,
+		from:     d.From,
+		timeout:  d.Timeout,
+	}, nil
+}
+
+func (s *SMTP) Notify(ctx context.Context, n *models.Notification) error {
+	msg, err := s.message(n)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("smtp dial: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("smtp deadline: %w", err)
+		}
+	}
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		return fmt.Errorf("smtp hello: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+
+	if s.username != "" {
+		if err = c.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err = c.Mail(s.from); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+
+	if err = c.Rcpt(n.To); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+
+	if _, err = w.Write(msg); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+
+	return c.Quit()
+}
+
+// message собирает письмо: только текст или multipart/alternative с текстом и HTML.
+func (s *SMTP) message(n *models.Notification) ([]byte, error) {
+	if _, err := mail.ParseAddress(n.To); err != nil {
+		return nil, fmt.Errorf("invalid recipient [%s]: %w", n.To, err)
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("message id: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", n.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", n.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), s.host)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if n.HTML == "" {
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+		if err := writeQuoted(&buf, n.Text); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", n.Text},
+		{"text/html; charset=utf-8", n.HTML},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("message part: %w", err)
+		}
+
+		if err = writeQuoted(pw, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("message close: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeQuoted(w io.Writer, text string) error {
+	qw := quotedprintable.NewWriter(w)
+	if _, err := qw.Write([]byte(text)); err != nil {
+		return fmt.Errorf("message body: %w", err)
+	}
+
+	if err := qw.Close(); err != nil {
+		return fmt.Errorf("message body: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,292 @@
+package notifier
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+)
+
+// fakeSMTP — SMTP-сервер в памяти: обслуживает одно соединение и
+// запоминает конверт и текст письма.
+type fakeSMTP struct {
+	listener net.Listener
+	rcptCode int
+	silent   bool
+
+	from string
+	to   string
+	data []byte
+	done chan struct{}
+}
+
+// start запускает сервер на свободном порту; настройки задаются до вызова.
+func (s *fakeSMTP) start(t *testing.T) *fakeSMTP {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	s.listener = l
+	s.done = make(chan struct{})
+	t.Cleanup(func() { l.Close() })
+
+	go s.serve()
+
+	return s
+}
+
+func (s *fakeSMTP) serve() {
+	defer close(s.done)
+
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if s.silent {
+		_, _ = io.Copy(io.Discard, conn)
+
+		return
+	}
+
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP fake")
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+
+		cmd := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			_ = tp.PrintfLine("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			_ = tp.PrintfLine("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.to = strings.Trim(line[len("RCPT TO:"):], "<>")
+			_ = tp.PrintfLine("%d recipient %s", s.rcptCode, s.to)
+		case cmd == "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+
+			if s.data, err = tp.ReadDotBytes(); err != nil {
+				return
+			}
+
+			_ = tp.PrintfLine("250 OK")
+		case cmd == "RSET", cmd == "NOOP":
+			_ = tp.PrintfLine("250 OK")
+		case cmd == "QUIT":
+			_ = tp.PrintfLine("221 bye")
+
+			return
+		default:
+			_ = tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTP) client(t *testing.T) *SMTP {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(s.listener.Addr().String())
+	if err != nil {
+		t.Fatalf("split addr: %v", err)
+	}
+
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatalf("port: %v", err)
+	}
+
+	c, err := NewSMTP(&SMTPDependencies{
+		Host:    host,
+		Port:    p,
+		From:    "todo@example.com",
+		Timeout: time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewSMTP: %v", err)
+	}
+
+	return c
+}
+
+func (s *fakeSMTP) wait(t *testing.T) {
+	t.Helper()
+
+	select {
+	case <-s.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fake SMTP server did not finish")
+	}
+}
+
+func TestSMTPNotifyText(t *testing.T) {
+	srv := (&fakeSMTP{rcptCode: 250}).start(t)
+
+	n := &models.Notification{
+		To:      "user@example.com",
+		Subject: "Напоминание: отчёт",
+		Text:    "Сдать отчёт\n\nDate: 2026-01-05\n",
+	}
+
+	if err := srv.client(t).Notify(context.Background(), n); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	srv.wait(t)
+
+	if srv.from != "todo@example.com" || srv.to != n.To {
+		t.Errorf("envelope = %q -> %q, want %q -> %q", srv.from, srv.to, "todo@example.com", n.To)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(srv.data)))
+	if err != nil {
+		t.Fatalf("read message: %v\n%s", err, srv.data)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+
+	if subject != n.Subject {
+		t.Errorf("Subject = %q, want %q", subject, n.Subject)
+	}
+
+	if got := msg.Header.Get("To"); got != n.To {
+		t.Errorf("To = %q, want %q", got, n.To)
+	}
+
+	if got := msg.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	if msg.Header.Get("Message-ID") == "" || msg.Header.Get("Date") == "" {
+		t.Errorf("missing Message-ID or Date header: %v", msg.Header)
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+
+	if got := strings.ReplaceAll(string(body), "\r\n", "\n"); got != n.Text {
+		t.Errorf("body = %q, want %q", got, n.Text)
+	}
+}
+
+func TestSMTPNotifyHTML(t *testing.T) {
+	srv := (&fakeSMTP{rcptCode: 250}).start(t)
+
+	n := &models.Notification{
+		To:      "user@example.com",
+		Subject: "Reminder",
+		Text:    "plain text",
+		HTML:    "<p>html &amp; text</p>",
+	}
+
+	if err := srv.client(t).Notify(context.Background(), n); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	srv.wait(t)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(srv.data)))
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q (%v), want multipart/alternative", msg.Header.Get("Content-Type"), err)
+	}
+
+	want := []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", n.Text},
+		{"text/html; charset=utf-8", n.HTML},
+	}
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for i, w := range want {
+		part, err := mr.NextRawPart()
+		if err != nil {
+			t.Fatalf("part %d: %v", i, err)
+		}
+
+		if got := part.Header.Get("Content-Type"); got != w.contentType {
+			t.Errorf("part %d Content-Type = %q, want %q", i, got, w.contentType)
+		}
+
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatalf("part %d body: %v", i, err)
+		}
+
+		if string(body) != w.body {
+			t.Errorf("part %d body = %q, want %q", i, body, w.body)
+		}
+	}
+
+	if _, err = mr.NextPart(); err != io.EOF {
+		t.Errorf("extra part after alternatives: %v", err)
+	}
+}
+
+func TestSMTPNotifyRejected(t *testing.T) {
+	srv := (&fakeSMTP{rcptCode: 550}).start(t)
+
+	err := srv.client(t).Notify(context.Background(), &models.Notification{To: "nobody@example.com", Text: "x"})
+	if err == nil || !strings.Contains(err.Error(), "rcpt to") {
+		t.Fatalf("Notify error = %v, want rcpt to failure", err)
+	}
+
+	srv.wait(t)
+
+	if srv.data != nil {
+		t.Errorf("message was sent after rejected recipient: %q", srv.data)
+	}
+}
+
+func TestSMTPNotifyTimeout(t *testing.T) {
+	srv := (&fakeSMTP{silent: true}).start(t)
+
+	c := srv.client(t)
+	c.timeout = 100 * time.Millisecond
+
+	start := time.Now()
+
+	if err := c.Notify(context.Background(), &models.Notification{To: "user@example.com", Text: "x"}); err == nil {
+		t.Fatal("Notify succeeded against a silent server")
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Notify took %s, want it bounded by the timeout", elapsed)
+	}
+}
+
+func TestSMTPNotifyInvalidRecipient(t *testing.T) {
+	srv := (&fakeSMTP{rcptCode: 250}).start(t)
+
+	if err := srv.client(t).Notify(context.Background(), &models.Notification{To: "not an address"}); err == nil {
+		t.Fatal("Notify accepted an invalid recipient")
+	}
+}
@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ServiceName = "todo-list"
+
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+
+	instrumentation = "github.com/sater-151/todo-list"
+)
+
+type (
+	// Dependencies: Endpoint — адрес OTLP/HTTP коллектора (host:port), нужен только
+	// для экспорта otlp. SampleRatio — доля трассировок, начатых в сервисе.
+	Dependencies struct {
+		Exporter    string  `validate:"oneof=none stdout otlp"`
+		Endpoint    string  `validate:"required_if=Exporter otlp,omitempty,hostname_port"`
+		Insecure    bool    `validate:"-"`
+		SampleRatio float64 `validate:"gte=0,lte=1"`
+		Version     string  `validate:"required"`
+	}
+
+	Tracing struct {
+		provider *sdktrace.TracerProvider
+	}
+)
+
+// New настраивает глобальный провайдер трассировок OpenTelemetry. С экспортом
+// none провайдер остаётся no-op и спаны не создаются.
+func New(ctx context.Context, d *Dependencies) (*Tracing, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("tracing.New", d, err)
+	}
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	if d.Exporter == ExporterNone {
+		return &Tracing{}, nil
+	}
+
+	exporter, err := newExporter(ctx, d)
+	if err != nil {
+		return nil, fmt.Errorf("creating %s trace exporter: %w", d.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(d.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("creating trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(d.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return &Tracing{provider: provider}, nil
+}
+
+func newExporter(ctx context.Context, d *Dependencies) (sdktrace.SpanExporter, error) {
+	if d.Exporter == ExporterStdout {
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(d.Endpoint)}
+	if d.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	return otlptracehttp.New(ctx, opts...)
+}
+
+// Shutdown отправляет накопленные спаны и останавливает экспорт.
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+
+	return t.provider.Shutdown(ctx)
+}
+
+// Tracer возвращает трассировщик сервиса из глобального провайдера.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
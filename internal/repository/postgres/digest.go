@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+)
+
+type DigestRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDigestRepo(pool *pgxpool.Pool) (*DigestRepo, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("postgres.NewDigestRepo: error = pool is nil")
+	}
+
+	return &DigestRepo{
+		pool: pool,
+	}, nil
+}
+
+// ClaimDigest резервирует отправку сводки получателю за день на lease. Возвращает
+// false, если сводка уже отправлена, отправка исчерпала попытки или её выполняет
+// другой экземпляр сервиса.
+func (r *DigestRepo) ClaimDigest(
+	ctx context.Context, recipient, day string, lease time.Duration,
+) (models.Digest, bool, error) {
+	const method = "ClaimDigest"
+
+	d := models.Digest{}
+
+	err := r.pool.QueryRow(ctx,
+		`INSERT INTO digests (recipient, day, lease_until) VALUES ($1, $2, now() + make_interval(secs => $3))
+		ON CONFLICT (recipient, day) DO UPDATE SET lease_until = EXCLUDED.lease_until
+		WHERE digests.status = 'pending' AND digests.lease_until <= now()
+		RETURNING recipient, day, status, attempts`,
+		recipient, day, lease.Seconds(),
+	).Scan(&d.Recipient, &d.Day, &d.Status, &d.Attempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return d, false, nil
+	}
+
+	if err != nil {
+		return d, false, errorspkg.NewRepoFailedError(method, "QueryRow", "digests", err)
+	}
+
+	return d, true, nil
+}
+
+// RecordDigest сохраняет результат отправки. Неудачная отправка в статусе pending
+// повторится после retryAfter.
+func (r *DigestRepo) RecordDigest(ctx context.Context, d *models.Digest, retryAfter time.Duration) error {
+	const method = "RecordDigest"
+
+	_, err := r.pool.Exec(ctx,
+		`UPDATE digests SET
+		status = $1,
+		attempts = $2,
+		last_error = $3,
+		sent_at = $4,
+		lease_until = now() + make_interval(secs => $5)
+		WHERE recipient = $6 AND day = $7`,
+		d.Status, d.Attempts, d.LastError, d.SentAt, retryAfter.Seconds(), d.Recipient, d.Day)
+	if err != nil {
+		return errorspkg.NewRepoFailedError(method, "Exec", "digests", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+)
+
+type FeedRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewFeedRepo(pool *pgxpool.Pool) (*FeedRepo, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("postgres.NewFeedRepo: error = pool is nil")
+	}
+
+	return &FeedRepo{
+		pool: pool,
+	}, nil
+}
+
+// InitFeedToken сохраняет token, если секрет фида ещё не создан, и возвращает
+// действующий секрет. Одновременные вызовы получают один и тот же секрет.
+func (r *FeedRepo) InitFeedToken(ctx context.Context, token string) (string, error) {
+	const method = "InitFeedToken"
+
+	err := r.pool.QueryRow(ctx,
+		`INSERT INTO feed_tokens (token) VALUES ($1)
+		ON CONFLICT (id) DO UPDATE SET token = feed_tokens.token
+		RETURNING token`,
+		token,
+	).Scan(&token)
+	if err != nil {
+		return "", errorspkg.NewRepoFailedError(method, "QueryRow", "feed_tokens", err)
+	}
+
+	return token, nil
+}
+
+// ReplaceFeedToken заменяет секрет фида на token.
+func (r *FeedRepo) ReplaceFeedToken(ctx context.Context, token string) error {
+	const method = "ReplaceFeedToken"
+
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO feed_tokens (token) VALUES ($1)
+		ON CONFLICT (id) DO UPDATE SET token = EXCLUDED.token, created_at = now()`,
+		token)
+	if err != nil {
+		return errorspkg.NewRepoFailedError(method, "Exec", "feed_tokens", err)
+	}
+
+	return nil
+}
+
+// SelectFeedToken возвращает секрет фида; false — секрет ещё не создан.
+func (r *FeedRepo) SelectFeedToken(ctx context.Context) (string, bool, error) {
+	const method = "SelectFeedToken"
+
+	var token string
+
+	err := r.pool.QueryRow(ctx, `SELECT token FROM feed_tokens`).Scan(&token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, errorspkg.NewRepoFailedError(method, "QueryRow", "feed_tokens", err)
+	}
+
+	return token, true, nil
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+)
+
+type HealthRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewHealthRepo(pool *pgxpool.Pool) (*HealthRepo, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("postgres.NewHealthRepo: error = pool is nil")
+	}
+
+	return &HealthRepo{
+		pool: pool,
+	}, nil
+}
+
+func (r *HealthRepo) Ping(ctx context.Context) error {
+	if err := r.pool.Ping(ctx); err != nil {
+		return errorspkg.NewRepoFailedError("Ping", "Ping", "postgres", err)
+	}
+
+	return nil
+}
+
+// MigrationVersion возвращает номер последней применённой миграции goose.
+func (r *HealthRepo) MigrationVersion(ctx context.Context) (int64, error) {
+	const method = "MigrationVersion"
+
+	var version int64
+
+	err := r.pool.QueryRow(ctx,
+		`SELECT COALESCE(max(version_id), 0) FROM goose_db_version WHERE is_applied`,
+	).Scan(&version)
+	if err != nil {
+		return 0, errorspkg.NewRepoFailedError(method, "QueryRow", "goose_db_version", err)
+	}
+
+	return version, nil
+}
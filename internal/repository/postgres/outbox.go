@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+)
+
+type OutboxRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutboxRepo(pool *pgxpool.Pool) (*OutboxRepo, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("postgres.NewOutboxRepo: error = pool is nil")
+	}
+
+	return &OutboxRepo{
+		pool: pool,
+	}, nil
+}
+
+const (
+	// outboxChannel — канал NOTIFY, в который триггер outbox_notify пишет ID новых событий.
+	outboxChannel = "outbox_events"
+
+	// outboxRetention — сколько обработанные события хранятся для ListenOutbox.
+	outboxRetention = 10 * time.Minute
+
+	outboxColumns = "id, event_type, COALESCE(task_uuid::text, ''), payload, created_at"
+)
+
+// ProcessOutbox блокирует до limit самых старых необработанных событий, передаёт их в fn
+// и помечает обработанными, если fn завершилась без ошибки. При ошибке события останутся
+// необработанными и будут переданы снова. Несколько экземпляров сервиса получают разные
+// события. Заодно удаляются события, обработанные раньше outboxRetention.
+func (r *OutboxRepo) ProcessOutbox(ctx context.Context, limit int, fn func([]models.TaskEvent) error) (int, error) {
+	const method = "ProcessOutbox"
+
+	var count int
+
+	err := inTx(ctx, r.pool, method, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			"DELETE FROM outbox WHERE processed_at < now() - make_interval(secs => $1)",
+			outboxRetention.Seconds())
+		if err != nil {
+			return errorspkg.NewRepoFailedError(method, "Exec", "outbox", err)
+		}
+
+		res, err := tx.Query(ctx,
+			`SELECT `+outboxColumns+` FROM outbox
+			WHERE processed_at IS NULL
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED`,
+			limit)
+		if err != nil {
+			return errorspkg.NewRepoFailedError(method, "Query", "outbox", err)
+		}
+
+		events, ids, err := scanOutbox(method, res)
+		if err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		if err = fn(events); err != nil {
+			return err
+		}
+
+		if _, err = tx.Exec(ctx, "UPDATE outbox SET processed_at = now() WHERE id = ANY($1)", ids); err != nil {
+			return errorspkg.NewRepoFailedError(method, "Exec", "outbox", err)
+		}
+
+		count = len(events)
+
+		return nil
+	})
+
+	return count, err
+}
+
+// ListenOutbox передаёт в fn каждое новое событие outbox сразу после фиксации его
+// транзакции, независимо от ProcessOutbox: события получает каждый экземпляр сервиса.
+// Работает на отдельном соединении до отмены ctx или обрыва связи; события, записанные
+// без соединения, не передаются.
+func (r *OutboxRepo) ListenOutbox(ctx context.Context, fn func([]models.TaskEvent)) error {
+	const method = "ListenOutbox"
+
+	pooled, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return errorspkg.NewRepoFailedError(method, "Acquire", "outbox", err)
+	}
+
+	// Соединение с подпиской не возвращается в пул.
+	conn := pooled.Hijack()
+	defer conn.Close(context.WithoutCancel(ctx)) //nolint:errcheck
+
+	if _, err = conn.Exec(ctx, "LISTEN "+outboxChannel); err != nil {
+		return errorspkg.NewRepoFailedError(method, "Exec", "outbox", err)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			return errorspkg.NewRepoFailedError(method, "WaitForNotification", "outbox", err)
+		}
+
+		id, err := strconv.ParseInt(notification.Payload, 10, 64)
+		if err != nil {
+			return errorspkg.NewRepoFailedError(method, "ParseInt", "outbox", err)
+		}
+
+		res, err := conn.Query(ctx, `SELECT `+outboxColumns+` FROM outbox WHERE id = $1`, id)
+		if err != nil {
+			return errorspkg.NewRepoFailedError(method, "Query", "outbox", err)
+		}
+
+		events, _, err := scanOutbox(method, res)
+		if err != nil {
+			return err
+		}
+
+		if len(events) > 0 {
+			fn(events)
+		}
+	}
+}
+
+// scanOutbox читает события outbox и их ID, закрывая res.
+func scanOutbox(method string, res pgx.Rows) ([]models.TaskEvent, []int64, error) {
+	defer res.Close()
+
+	var (
+		events []models.TaskEvent
+		ids    []int64
+	)
+
+	for res.Next() {
+		var (
+			id      int64
+			payload []byte
+			event   models.TaskEvent
+		)
+
+		if err := res.Scan(&id, &event.Type, &event.TaskID, &payload, &event.Time); err != nil {
+			return nil, nil, errorspkg.NewRepoFailedError(method, "Scan", "outbox", err)
+		}
+
+		if len(payload) > 0 {
+			event.Task = &models.Task{}
+			if err := json.Unmarshal(payload, event.Task); err != nil {
+				return nil, nil, errorspkg.NewRepoFailedError(method, "Unmarshal", "outbox", err)
+			}
+		}
+
+		event.ID = strconv.FormatInt(id, 10)
+		events = append(events, event)
+		ids = append(ids, id)
+	}
+
+	if err := res.Err(); err != nil {
+		return nil, nil, errorspkg.NewRepoFailedError(method, "Rows", "outbox", err)
+	}
+
+	return events, ids, nil
+}
+
+// insertOutbox пишет событие в outbox в транзакции изменения задачи.
+// Для удаления хранится только uuid задачи, для reset — ничего.
+func insertOutbox(ctx context.Context, tx pgx.Tx, method, eventType string, task *models.Task) error {
+	var (
+		taskUUID *string
+		payload  []byte
+	)
+
+	if task != nil {
+		taskUUID = &task.ID
+
+		if eventType != models.TaskEventDeleted {
+			data, err := json.Marshal(task)
+			if err != nil {
+				return errorspkg.NewRepoFailedError(method, "Marshal", "outbox", err)
+			}
+
+			payload = data
+		}
+	}
+
+	_, err := tx.Exec(ctx, "INSERT INTO outbox (event_type, task_uuid, payload) VALUES ($1, $2, $3)",
+		eventType, taskUUID, payload)
+	if err != nil {
+		return errorspkg.NewRepoFailedError(method, "Exec", "outbox", err)
+	}
+
+	return nil
+}
+
+// inTx выполняет fn в транзакции и фиксирует её, если fn не вернула ошибку.
+func inTx(ctx context.Context, pool *pgxpool.Pool, method string, fn func(tx pgx.Tx) error) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return errorspkg.NewRepoFailedError(method, "Begin", "transaction", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errorspkg.NewRepoFailedError(method, "Commit", "transaction", err)
+	}
+
+	return nil
+}
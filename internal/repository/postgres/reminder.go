@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+)
+
+type ReminderRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewReminderRepo(pool *pgxpool.Pool) (*ReminderRepo, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("postgres.NewReminderRepo: error = pool is nil")
+	}
+
+	return &ReminderRepo{
+		pool: pool,
+	}, nil
+}
+
+func (r *ReminderRepo) InsertReminder(ctx context.Context, reminder *models.Reminder) (string, error) {
+	const method = "InsertReminder"
+
+	reminderUUID, err := uuid.NewV7()
+	if err != nil {
+		reminderUUID = uuid.New()
+	}
+
+	_, err = r.pool.Exec(ctx,
+		"INSERT INTO reminders (uuid, task_uuid, days_before, at_time, email) VALUES ($1, $2, $3, $4::time, $5)",
+		reminderUUID.String(), reminder.TaskID, reminder.DaysBefore, reminder.Time, reminder.Email)
+	if err != nil {
+		return "", errorspkg.NewRepoFailedError(method, "Exec", "reminders", err)
+	}
+
+	return reminderUUID.String(), nil
+}
+
+func (r *ReminderRepo) DeleteReminder(ctx context.Context, reminderUUID string) error {
+	const method = "DeleteReminder"
+
+	_, err := r.pool.Exec(ctx, "DELETE FROM reminders WHERE uuid = $1", reminderUUID)
+	if err != nil {
+		return errorspkg.NewRepoFailedError(method, "Exec", "reminders", err)
+	}
+
+	return nil
+}
+
+// SelectReminders возвращает напоминания задачи или все напоминания, если taskUUID пуст.
+func (r *ReminderRepo) SelectReminders(ctx context.Context, taskUUID string) ([]models.Reminder, error) {
+	const method = "SelectReminders"
+
+	row := `SELECT uuid, task_uuid, days_before, to_char(at_time, 'HH24:MI'), email, occurrence,
+		status, attempts, last_error, sent_at FROM reminders`
+
+	var args []any
+	if taskUUID != "" {
+		row += " WHERE task_uuid = $1"
+		args = append(args, taskUUID)
+	}
+
+	row += " ORDER BY created_at"
+
+	res, err := r.pool.Query(ctx, row, args...)
+	if err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Query", "reminders", err)
+	}
+	defer res.Close()
+
+	var reminders []models.Reminder
+	for res.Next() {
+		rm := models.Reminder{}
+		err = res.Scan(&rm.ID, &rm.TaskID, &rm.DaysBefore, &rm.Time, &rm.Email, &rm.Occurrence,
+			&rm.Status, &rm.Attempts, &rm.LastError, &rm.SentAt)
+		if err != nil {
+			return nil, errorspkg.NewRepoFailedError(method, "Scan", "reminders", err)
+		}
+
+		reminders = append(reminders, rm)
+	}
+
+	if err = res.Err(); err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Rows", "reminders", err)
+	}
+
+	return reminders, nil
+}
+
+// ClaimReminders выбирает напоминания, время которых пришло для текущей даты задачи,
+// и откладывает их на lease, чтобы другой экземпляр сервиса не отправил их одновременно.
+// Время срабатывания считается в часовом поясе timezone. Напоминания, время которых
+// прошло ещё до их создания, не срабатывают.
+func (r *ReminderRepo) ClaimReminders(
+	ctx context.Context, limit int, lease time.Duration, timezone string,
+) ([]models.Reminder, error) {
+	const method = "ClaimReminders"
+
+	res, err := r.pool.Query(ctx,
+		`WITH due AS (
+			SELECT p.uuid, ((to_date(ps.date::text, 'YYYYMMDD') - p.days_before) + p.at_time) AT TIME ZONE $3 AS fire_at
+			FROM reminders p JOIN scheduler ps ON ps.uuid = p.task_uuid
+			WHERE (p.occurrence <> ps.date::text OR (p.status = 'pending' AND p.next_attempt_at <= now()))
+			AND ((to_date(ps.date::text, 'YYYYMMDD') - p.days_before) + p.at_time) AT TIME ZONE $3
+				BETWEEN p.created_at AND now()
+			ORDER BY fire_at
+			LIMIT $1
+			FOR UPDATE OF p SKIP LOCKED
+		)
+		UPDATE reminders r SET
+		attempts = CASE WHEN r.occurrence = s.date::text THEN r.attempts ELSE 0 END,
+		status = 'pending',
+		occurrence = s.date::text,
+		next_attempt_at = now() + make_interval(secs => $2)
+		FROM scheduler s, due
+		WHERE s.uuid = r.task_uuid AND due.uuid = r.uuid
+		RETURNING r.uuid, r.task_uuid, r.days_before, to_char(r.at_time, 'HH24:MI'), r.email, r.occurrence,
+		r.status, r.attempts, due.fire_at, s.date::text, s.title, COALESCE(s.comment, ''), s.repeat`,
+		limit, lease.Seconds(), timezone)
+	if err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Query", "reminders", err)
+	}
+	defer res.Close()
+
+	var reminders []models.Reminder
+	for res.Next() {
+		rm := models.Reminder{Task: &models.Task{}}
+		err = res.Scan(&rm.ID, &rm.TaskID, &rm.DaysBefore, &rm.Time, &rm.Email, &rm.Occurrence,
+			&rm.Status, &rm.Attempts, &rm.FireAt, &rm.Task.Date, &rm.Task.Title, &rm.Task.Comment, &rm.Task.Repeat)
+		if err != nil {
+			return nil, errorspkg.NewRepoFailedError(method, "Scan", "reminders", err)
+		}
+
+		rm.Task.ID = rm.TaskID
+		reminders = append(reminders, rm)
+	}
+
+	if err = res.Err(); err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Rows", "reminders", err)
+	}
+
+	return reminders, nil
+}
+
+// RecordReminder сохраняет результат попытки отправки напоминания.
+func (r *ReminderRepo) RecordReminder(ctx context.Context, reminder *models.Reminder) error {
+	const method = "RecordReminder"
+
+	_, err := r.pool.Exec(ctx,
+		`UPDATE reminders SET
+		status = $1,
+		attempts = $2,
+		last_error = $3,
+		next_attempt_at = COALESCE($4, next_attempt_at),
+		sent_at = $5
+		WHERE uuid = $6 AND occurrence = $7`,
+		reminder.Status, reminder.Attempts, reminder.LastError, reminder.NextAttemptAt, reminder.SentAt,
+		reminder.ID, reminder.Occurrence)
+	if err != nil {
+		return errorspkg.NewRepoFailedError(method, "Exec", "reminders", err)
+	}
+
+	return nil
+}
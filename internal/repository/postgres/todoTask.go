@@ -2,9 +2,14 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sater-151/todo-list/internal/models"
 	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
@@ -24,6 +29,7 @@ func NewTodoTaskRepo(pool *pgxpool.Pool) (*TodoTaskRepo, error) {
 	}, nil
 }
 
+// InsertTask добавляет задачу и событие created в outbox в одной транзакции.
 func (r *TodoTaskRepo) InsertTask(ctx context.Context, task *models.Task) (string, error) {
 	const method = "InsertTask"
 
@@ -32,97 +38,465 @@ func (r *TodoTaskRepo) InsertTask(ctx context.Context, task *models.Task) (strin
 		taskUUID = uuid.New()
 	}
 
-	_, err = r.pool.Exec(
-		ctx,
-		`INSERT INTO scheduler (
-		uuid, 
-		date, 
-		title, 
-		comment, 
-		repeat
+	err = inTx(ctx, r.pool, method, func(tx pgx.Tx) error {
+		_, err := tx.Exec(
+			ctx,
+			`INSERT INTO scheduler (
+			uuid, 
+			date, 
+			title, 
+			comment, 
+			repeat
+			)
+			 VALUES ($1, $2, $3, $4, $5)`,
+			taskUUID.String(), task.Date, task.Title, task.Comment, task.Repeat,
 		)
-		 VALUES ($1, $2, $3, $4, $5)`,
-		taskUUID.String(), task.Date, task.Title, task.Comment, task.Repeat,
-	)
+		if err != nil {
+			return errorspkg.NewRepoFailedError(method, "Exec", "tasks", err)
+		}
+
+		created := *task
+		created.ID = taskUUID.String()
+
+		return insertOutbox(ctx, tx, method, models.TaskEventCreated, &created)
+	})
 	if err != nil {
-		return "", errorspkg.NewRepoFailedError(method, "Exec", "tasks", err)
+		return "", err
 	}
 
 	return taskUUID.String(), nil
 }
 
-func (r *TodoTaskRepo) UpdateTask(ctx context.Context, task *models.Task) error {
-	const method = "UpdateTask"
+// UpsertTasks в одной транзакции добавляет задачи или обновляет существующие по uuid.
+// Задачам без ID присваивается новый uuid. При replace удаляются только задачи, которых
+// нет среди tasks, поэтому у остальных сохраняются напоминания и пропущенные даты.
+// В outbox пишется одно событие reset.
+func (r *TodoTaskRepo) UpsertTasks(ctx context.Context, tasks []models.Task, replace bool) error {
+	const method = "UpsertTasks"
 
-	_, err := r.pool.Exec(ctx, "UPDATE scheduler SET date = $1, title = $2, comment = $3, repeat = $4 WHERE uuid = $5",
-		task.Date,
-		task.Title,
-		task.Comment,
-		task.Repeat,
-		task.ID)
-	if err != nil {
-		return errorspkg.NewRepoFailedError(method, "Exec", "tasks", err)
+	ids := make([]string, len(tasks))
+
+	for i := range tasks {
+		if tasks[i].ID == "" {
+			taskUUID, err := uuid.NewV7()
+			if err != nil {
+				taskUUID = uuid.New()
+			}
+
+			tasks[i].ID = taskUUID.String()
+		}
+
+		ids[i] = tasks[i].ID
 	}
 
-	return nil
+	return inTx(ctx, r.pool, method, func(tx pgx.Tx) error {
+		if replace {
+			if _, err := tx.Exec(ctx, "DELETE FROM scheduler WHERE uuid <> ALL($1::uuid[])", ids); err != nil {
+				return errorspkg.NewRepoFailedError(method, "Exec", "tasks", err)
+			}
+		}
+
+		for i := range tasks {
+			_, err := tx.Exec(ctx,
+				`INSERT INTO scheduler (uuid, date, title, comment, repeat, snoozed_from)
+				VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::bigint)
+				ON CONFLICT (uuid) DO UPDATE SET
+				date = EXCLUDED.date, title = EXCLUDED.title, comment = EXCLUDED.comment, repeat = EXCLUDED.repeat,
+				snoozed_from = EXCLUDED.snoozed_from`,
+				tasks[i].ID, tasks[i].Date, tasks[i].Title, tasks[i].Comment, tasks[i].Repeat, tasks[i].SnoozedFrom,
+			)
+			if err != nil {
+				return errorspkg.NewRepoFailedError(method, "Exec", "tasks", err)
+			}
+		}
+
+		return insertOutbox(ctx, tx, method, models.TaskEventReset, nil)
+	})
+}
+
+// UpdateTask обновляет задачу и пишет в outbox событие eventType (updated или advanced).
+// Смена даты отменяет откладывание задачи.
+func (r *TodoTaskRepo) UpdateTask(ctx context.Context, task *models.Task, eventType string) error {
+	const method = "UpdateTask"
+
+	return inTx(ctx, r.pool, method, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			`UPDATE scheduler SET
+			snoozed_from = CASE WHEN date = $1 THEN snoozed_from END,
+			date = $1, title = $2, comment = $3, repeat = $4
+			WHERE uuid = $5`,
+			task.Date,
+			task.Title,
+			task.Comment,
+			task.Repeat,
+			task.ID)
+		if err != nil {
+			return errorspkg.NewRepoFailedError(method, "Exec", "tasks", err)
+		}
+
+		return insertOutbox(ctx, tx, method, eventType, task)
+	})
+}
+
+// SnoozeTask переносит задачу на task.Date, запоминая дату до первого откладывания
+// в task.SnoozedFrom, и пишет в outbox событие updated. Напоминания задачи переносятся
+// вместе с ней: счётчик попыток сбрасывается, и они срабатывают относительно новой даты.
+// Возвращает false, если задачи нет.
+func (r *TodoTaskRepo) SnoozeTask(ctx context.Context, task *models.Task) (bool, error) {
+	const method = "SnoozeTask"
+
+	found := false
+
+	err := inTx(ctx, r.pool, method, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx,
+			`UPDATE scheduler SET snoozed_from = COALESCE(snoozed_from, date), date = $1
+			WHERE uuid = $2
+			RETURNING title, COALESCE(comment, ''), repeat, snoozed_from::text`,
+			task.Date, task.ID,
+		).Scan(&task.Title, &task.Comment, &task.Repeat, &task.SnoozedFrom)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+
+		if err != nil {
+			return errorspkg.NewRepoFailedError(method, "QueryRow", "tasks", err)
+		}
+
+		found = true
+
+		// Пустой occurrence отменяет и запись результата уже начатой отправки.
+		_, err = tx.Exec(ctx,
+			`UPDATE reminders SET
+			occurrence = '', status = 'pending', attempts = 0, last_error = '', sent_at = NULL,
+			next_attempt_at = now()
+			WHERE task_uuid = $1 AND occurrence <> $2`,
+			task.ID, task.Date)
+		if err != nil {
+			return errorspkg.NewRepoFailedError(method, "Exec", "reminders", err)
+		}
+
+		return insertOutbox(ctx, tx, method, models.TaskEventUpdated, task)
+	})
+
+	return found, err
 }
 
-func (r *TodoTaskRepo) DeleteTask(ctx context.Context, taskUUID string) error {
+// DeleteTask удаляет задачу и пишет в outbox событие eventType (deleted или done).
+func (r *TodoTaskRepo) DeleteTask(ctx context.Context, task *models.Task, eventType string) error {
 	const method = "DeleteTask"
 
-	_, err := r.pool.Exec(ctx, "DELETE FROM scheduler WHERE uuid = $1", taskUUID)
+	return inTx(ctx, r.pool, method, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "DELETE FROM scheduler WHERE uuid = $1", task.ID)
+		if err != nil {
+			return errorspkg.NewRepoFailedError(method, "Exec", "tasks", err)
+		}
+
+		return insertOutbox(ctx, tx, method, eventType, task)
+	})
+}
+
+const (
+	headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5, MaxFragments=2"
+
+	// headlineText — текст задачи для ts_headline. Фрагмент отдаётся клиенту как HTML,
+	// поэтому текст экранируется до выделения: в нём остаются только теги <mark>.
+	// Парсер поиска считает мнемоники вида &lt; отдельными лексемами и не режет их.
+	headlineText = `replace(replace(replace(replace(replace(title || ' ' || COALESCE(comment, ''),
+		'&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+)
+
+var (
+	sortColumns = map[string]string{
+		"date":  "date",
+		"title": "title",
+	}
+	sortTypes = map[string]string{
+		"":     "",
+		"ASC":  " ASC",
+		"DESC": " DESC",
+	}
+)
+
+func (r *TodoTaskRepo) Select(ctx context.Context, selectConfig *models.SelectConfig) ([]models.Task, error) {
+	var listTask []models.Task
+
+	err := r.SelectEach(ctx, selectConfig, func(task *models.Task) error {
+		listTask = append(listTask, *task)
+
+		return nil
+	})
 	if err != nil {
-		return errorspkg.NewRepoFailedError(method, "Exec", "tasks", err)
+		return nil, err
 	}
 
-	return nil
+	return listTask, nil
 }
 
-func (r *TodoTaskRepo) Select(ctx context.Context, selectConfig *models.SelectConfig) ([]models.Task, error) {
-	const method = "Select"
+// SelectEach выполняет выборку и передаёт задачи в fn по одной, не загружая их все в память.
+func (r *TodoTaskRepo) SelectEach(
+	ctx context.Context, selectConfig *models.SelectConfig, fn func(*models.Task) error,
+) error {
+	const method = "SelectEach"
 
-	row := fmt.Sprintf("SELECT * FROM %s", selectConfig.Table)
+	var (
+		conds []string
+		args  []any
+	)
 
-	if selectConfig.Search != "" || selectConfig.Date != "" || selectConfig.ID != "" {
-		row += " WHERE"
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
 	}
 
+	columns := "uuid, date, title, comment, repeat, COALESCE(snoozed_from::text, '')"
+	from := selectConfig.Table
+
 	if selectConfig.Search != "" {
-		row += fmt.Sprintf(" title LIKE %s OR comment LIKE %s", "'%"+selectConfig.Search+"%'", "'%"+selectConfig.Search+"%'")
+		args = append(args, searchLanguage(selectConfig.Search), selectConfig.Search)
+		from += fmt.Sprintf(", websearch_to_tsquery($%d::regconfig, $%d) AS query", len(args)-1, len(args))
+		columns += fmt.Sprintf(", ts_headline($%d::regconfig, %s, query, '%s')",
+			len(args)-1, headlineText, headlineOptions)
+		conds = append(conds, "search_vector @@ query")
 	}
 
 	if selectConfig.Date != "" {
-		row += fmt.Sprintf(" date = '%s'", selectConfig.Date)
+		where("date = $%d", selectConfig.Date)
 	}
 
 	if selectConfig.ID != "" {
-		row += fmt.Sprintf(" uuid = '%s'", selectConfig.ID)
+		where("uuid = $%d", selectConfig.ID)
+	}
+
+	if selectConfig.DateFrom != "" {
+		where("date >= $%d", selectConfig.DateFrom)
+	}
+
+	if selectConfig.DateTo != "" {
+		where("date <= $%d", selectConfig.DateTo)
+	}
+
+	if selectConfig.DateBefore != "" {
+		where("date < $%d", selectConfig.DateBefore)
+	}
+
+	for _, tag := range selectConfig.Tags {
+		where(`COALESCE(comment, '') ~* $%d`, `(^|\s)#`+regexp.QuoteMeta(tag)+`(\s|$)`)
+	}
+
+	if selectConfig.Repeating != nil {
+		if *selectConfig.Repeating {
+			conds = append(conds, "repeat <> ''")
+		} else {
+			conds = append(conds, "repeat = ''")
+		}
+	}
+
+	if selectConfig.HasComment != nil {
+		if *selectConfig.HasComment {
+			conds = append(conds, "COALESCE(comment, '') <> ''")
+		} else {
+			conds = append(conds, "COALESCE(comment, '') = ''")
+		}
+	}
+
+	row := fmt.Sprintf("SELECT %s FROM %s", columns, from)
+
+	if len(conds) > 0 {
+		row += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var orderBy []string
+	if selectConfig.Search != "" {
+		orderBy = append(orderBy, "ts_rank(search_vector, query) DESC")
 	}
 
 	if selectConfig.Sort != "" {
-		row += fmt.Sprintf(" ORDER BY %s %s", selectConfig.Sort, selectConfig.TypeSort)
+		column, ok := sortColumns[selectConfig.Sort]
+		if !ok {
+			return errorspkg.NewRepoFailedError(method, "Sort", "tasks",
+				fmt.Errorf("unknown sort column [%s]", selectConfig.Sort))
+		}
+
+		order, ok := sortTypes[selectConfig.TypeSort]
+		if !ok {
+			return errorspkg.NewRepoFailedError(method, "Sort", "tasks",
+				fmt.Errorf("unknown sort type [%s]", selectConfig.TypeSort))
+		}
+
+		orderBy = append(orderBy, column+order)
+	}
+
+	if len(orderBy) > 0 {
+		row += " ORDER BY " + strings.Join(orderBy, ", ")
 	}
 
 	if selectConfig.Limit != "" {
-		row += fmt.Sprintf(" LIMIT %s", selectConfig.Limit)
+		args = append(args, selectConfig.Limit)
+		row += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	if selectConfig.Offset != "" {
+		args = append(args, selectConfig.Offset)
+		row += fmt.Sprintf(" OFFSET $%d", len(args))
 	}
 
-	res, err := r.pool.Query(ctx, row)
+	res, err := r.pool.Query(ctx, row, args...)
 	if err != nil {
-		return nil, errorspkg.NewRepoFailedError(method, "Query", "tasks", err)
+		return errorspkg.NewRepoFailedError(method, "Query", "tasks", err)
 	}
 	defer res.Close()
 
-	var listTask []models.Task
 	for res.Next() {
 		task := models.Task{}
-		err = res.Scan(&task.ID, &task.Date, &task.Title, &task.Comment, &task.Repeat)
+		dest := []any{&task.ID, &task.Date, &task.Title, &task.Comment, &task.Repeat, &task.SnoozedFrom}
+		if selectConfig.Search != "" {
+			dest = append(dest, &task.Snippet)
+		}
+
+		err = res.Scan(dest...)
 		if err != nil {
-			return nil, errorspkg.NewRepoFailedError(method, "Scan", "tasks", err)
+			return errorspkg.NewRepoFailedError(method, "Scan", "tasks", err)
+		}
+
+		if err = fn(&task); err != nil {
+			return err
 		}
+	}
 
-		listTask = append(listTask, task)
+	if err = res.Err(); err != nil {
+		return errorspkg.NewRepoFailedError(method, "Rows", "tasks", err)
 	}
 
-	return listTask, nil
+	return nil
+}
+
+// searchLanguage выбирает конфигурацию полнотекстового поиска по алфавиту запроса.
+func searchLanguage(search string) string {
+	for _, r := range search {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return "russian"
+		}
+	}
+
+	return "english"
+}
+
+// RollForward под advisory-блокировкой переносит повторяющиеся задачи с датой раньше before.
+// advance меняет дату задачи и возвращает пропущенные даты; задача, дата которой не
+// изменилась, пропускается. Возвращает число перенесённых задач и false, если перенос
+// уже выполняет другой экземпляр сервиса.
+func (r *TodoTaskRepo) RollForward(
+	ctx context.Context, before string, advance func(task *models.Task) []string,
+) (int, bool, error) {
+	const method = "RollForward"
+
+	var (
+		count  int
+		locked bool
+	)
+
+	err := inTx(ctx, r.pool, method, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock(hashtext('todo-list:roll-forward'))").Scan(&locked)
+		if err != nil {
+			return errorspkg.NewRepoFailedError(method, "QueryRow", "advisory lock", err)
+		}
+
+		if !locked {
+			return nil
+		}
+
+		res, err := tx.Query(ctx,
+			`SELECT uuid, date, title, COALESCE(comment, ''), repeat, COALESCE(snoozed_from::text, '')
+			FROM scheduler
+			WHERE repeat <> '' AND date < $1
+			ORDER BY date
+			FOR UPDATE`,
+			before)
+		if err != nil {
+			return errorspkg.NewRepoFailedError(method, "Query", "tasks", err)
+		}
+
+		tasks, err := pgx.CollectRows(res, func(row pgx.CollectableRow) (models.Task, error) {
+			var task models.Task
+			err := row.Scan(&task.ID, &task.Date, &task.Title, &task.Comment, &task.Repeat, &task.SnoozedFrom)
+
+			return task, err
+		})
+		if err != nil {
+			return errorspkg.NewRepoFailedError(method, "Scan", "tasks", err)
+		}
+
+		for i := range tasks {
+			date := tasks[i].Date
+
+			missed := advance(&tasks[i])
+			if tasks[i].Date == date {
+				continue
+			}
+
+			// Перенесённая задача снова идёт по расписанию, откладывание забывается.
+			_, err = tx.Exec(ctx,
+				"UPDATE scheduler SET date = $1, snoozed_from = NULL WHERE uuid = $2", tasks[i].Date, tasks[i].ID)
+			if err != nil {
+				return errorspkg.NewRepoFailedError(method, "Exec", "tasks", err)
+			}
+
+			_, err = tx.Exec(ctx,
+				"INSERT INTO missed_tasks (task_uuid, date) SELECT $1, unnest($2::text[])",
+				tasks[i].ID, missed)
+			if err != nil {
+				return errorspkg.NewRepoFailedError(method, "Exec", "missed_tasks", err)
+			}
+
+			tasks[i].SnoozedFrom = ""
+
+			if err = insertOutbox(ctx, tx, method, models.TaskEventAdvanced, &tasks[i]); err != nil {
+				return err
+			}
+
+			count++
+		}
+
+		return nil
+	})
+
+	return count, locked, err
+}
+
+// SelectMissed возвращает пропущенные даты задачи или всех задач, если taskUUID пуст,
+// новые первыми.
+func (r *TodoTaskRepo) SelectMissed(ctx context.Context, taskUUID string, limit int) ([]models.MissedTask, error) {
+	const method = "SelectMissed"
+
+	row := "SELECT m.task_uuid, s.title, m.date, m.created_at FROM missed_tasks m JOIN scheduler s ON s.uuid = m.task_uuid"
+
+	args := []any{limit}
+	if taskUUID != "" {
+		row += " WHERE m.task_uuid = $2"
+		args = append(args, taskUUID)
+	}
+
+	row += " ORDER BY m.date DESC, m.id DESC LIMIT $1"
+
+	res, err := r.pool.Query(ctx, row, args...)
+	if err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Query", "missed_tasks", err)
+	}
+	defer res.Close()
+
+	var missed []models.MissedTask
+	for res.Next() {
+		m := models.MissedTask{}
+		if err = res.Scan(&m.TaskID, &m.Title, &m.Date, &m.CreatedAt); err != nil {
+			return nil, errorspkg.NewRepoFailedError(method, "Scan", "missed_tasks", err)
+		}
+
+		missed = append(missed, m)
+	}
+
+	if err = res.Err(); err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Rows", "missed_tasks", err)
+	}
+
+	return missed, nil
 }
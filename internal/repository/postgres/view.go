@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+)
+
+type ViewRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewViewRepo(pool *pgxpool.Pool) (*ViewRepo, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("postgres.NewViewRepo: error = pool is nil")
+	}
+
+	return &ViewRepo{
+		pool: pool,
+	}, nil
+}
+
+func (r *ViewRepo) InsertView(ctx context.Context, view *models.View) (string, error) {
+	const method = "InsertView"
+
+	viewUUID, err := uuid.NewV7()
+	if err != nil {
+		viewUUID = uuid.New()
+	}
+
+	_, err = r.pool.Exec(ctx, "INSERT INTO views (uuid, name, filter) VALUES ($1, $2, $3)",
+		viewUUID.String(), view.Name, view.Filter)
+	if err != nil {
+		return "", errorspkg.NewRepoFailedError(method, "Exec", "views", err)
+	}
+
+	return viewUUID.String(), nil
+}
+
+func (r *ViewRepo) UpdateView(ctx context.Context, view *models.View) (bool, error) {
+	const method = "UpdateView"
+
+	tag, err := r.pool.Exec(ctx, "UPDATE views SET name = $1, filter = $2 WHERE uuid = $3",
+		view.Name, view.Filter, view.ID)
+	if err != nil {
+		return false, errorspkg.NewRepoFailedError(method, "Exec", "views", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *ViewRepo) DeleteView(ctx context.Context, viewUUID string) error {
+	const method = "DeleteView"
+
+	_, err := r.pool.Exec(ctx, "DELETE FROM views WHERE uuid = $1", viewUUID)
+	if err != nil {
+		return errorspkg.NewRepoFailedError(method, "Exec", "views", err)
+	}
+
+	return nil
+}
+
+// SelectViews возвращает представление с указанным uuid или все представления, если uuid пуст.
+func (r *ViewRepo) SelectViews(ctx context.Context, viewUUID string) ([]models.View, error) {
+	const method = "SelectViews"
+
+	row := "SELECT uuid, name, filter FROM views"
+
+	var args []any
+	if viewUUID != "" {
+		row += " WHERE uuid = $1"
+		args = append(args, viewUUID)
+	}
+
+	row += " ORDER BY created_at"
+
+	res, err := r.pool.Query(ctx, row, args...)
+	if err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Query", "views", err)
+	}
+	defer res.Close()
+
+	var views []models.View
+	for res.Next() {
+		view := models.View{}
+		if err = res.Scan(&view.ID, &view.Name, &view.Filter); err != nil {
+			return nil, errorspkg.NewRepoFailedError(method, "Scan", "views", err)
+		}
+
+		views = append(views, view)
+	}
+
+	if err = res.Err(); err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Rows", "views", err)
+	}
+
+	return views, nil
+}
@@ -0,0 +1,265 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+)
+
+type WebhookRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepo(pool *pgxpool.Pool) (*WebhookRepo, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("postgres.NewWebhookRepo: error = pool is nil")
+	}
+
+	return &WebhookRepo{
+		pool: pool,
+	}, nil
+}
+
+func (r *WebhookRepo) InsertWebhook(ctx context.Context, webhook *models.Webhook) (string, error) {
+	const method = "InsertWebhook"
+
+	webhookUUID, err := uuid.NewV7()
+	if err != nil {
+		webhookUUID = uuid.New()
+	}
+
+	_, err = r.pool.Exec(ctx, "INSERT INTO webhooks (uuid, url, secret, events) VALUES ($1, $2, $3, $4)",
+		webhookUUID.String(), webhook.URL, webhook.Secret, webhook.Events)
+	if err != nil {
+		return "", errorspkg.NewRepoFailedError(method, "Exec", "webhooks", err)
+	}
+
+	return webhookUUID.String(), nil
+}
+
+// UpdateWebhook меняет адрес, события и признак включения. Пустой секрет не меняется.
+// Повторное включение сбрасывает счётчик ошибок.
+func (r *WebhookRepo) UpdateWebhook(ctx context.Context, webhook *models.Webhook) (bool, error) {
+	const method = "UpdateWebhook"
+
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE webhooks SET
+		url = $1,
+		events = $2,
+		enabled = $3,
+		secret = COALESCE(NULLIF($4, ''), secret),
+		failures = CASE WHEN $3 THEN 0 ELSE failures END,
+		disabled_reason = CASE WHEN $3 THEN '' ELSE disabled_reason END
+		WHERE uuid = $5`,
+		webhook.URL, webhook.Events, webhook.Enabled, webhook.Secret, webhook.ID)
+	if err != nil {
+		return false, errorspkg.NewRepoFailedError(method, "Exec", "webhooks", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *WebhookRepo) DeleteWebhook(ctx context.Context, webhookUUID string) error {
+	const method = "DeleteWebhook"
+
+	_, err := r.pool.Exec(ctx, "DELETE FROM webhooks WHERE uuid = $1", webhookUUID)
+	if err != nil {
+		return errorspkg.NewRepoFailedError(method, "Exec", "webhooks", err)
+	}
+
+	return nil
+}
+
+// SelectWebhooks возвращает вебхук с указанным uuid или все вебхуки, если uuid пуст.
+func (r *WebhookRepo) SelectWebhooks(ctx context.Context, webhookUUID string) ([]models.Webhook, error) {
+	const method = "SelectWebhooks"
+
+	row := "SELECT uuid, url, secret, events, enabled, failures, disabled_reason FROM webhooks"
+
+	var args []any
+	if webhookUUID != "" {
+		row += " WHERE uuid = $1"
+		args = append(args, webhookUUID)
+	}
+
+	row += " ORDER BY created_at"
+
+	res, err := r.pool.Query(ctx, row, args...)
+	if err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Query", "webhooks", err)
+	}
+	defer res.Close()
+
+	var webhooks []models.Webhook
+	for res.Next() {
+		w := models.Webhook{}
+		if err = res.Scan(&w.ID, &w.URL, &w.Secret, &w.Events, &w.Enabled, &w.Failures, &w.DisabledReason); err != nil {
+			return nil, errorspkg.NewRepoFailedError(method, "Scan", "webhooks", err)
+		}
+
+		webhooks = append(webhooks, w)
+	}
+
+	if err = res.Err(); err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Rows", "webhooks", err)
+	}
+
+	return webhooks, nil
+}
+
+// EnqueueDeliveries создаёт доставки события для всех включённых вебхуков,
+// подписанных на его тип (пустой список событий означает все события).
+// Уже созданные доставки того же события не дублируются.
+func (r *WebhookRepo) EnqueueDeliveries(ctx context.Context, eventID, eventType string, payload []byte) (int, error) {
+	const method = "EnqueueDeliveries"
+
+	tag, err := r.pool.Exec(ctx,
+		`INSERT INTO webhook_deliveries (uuid, webhook_uuid, event_id, event_type, payload)
+		SELECT gen_random_uuid(), uuid, $1, $2, $3 FROM webhooks
+		WHERE enabled AND (cardinality(events) = 0 OR $2 = ANY(events))
+		ON CONFLICT (webhook_uuid, event_id) DO NOTHING`,
+		eventID, eventType, payload)
+	if err != nil {
+		return 0, errorspkg.NewRepoFailedError(method, "Exec", "webhook_deliveries", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// ClaimDeliveries выбирает готовые к отправке доставки и откладывает их следующую
+// попытку на lease, чтобы другой экземпляр сервиса не отправил их одновременно.
+func (r *WebhookRepo) ClaimDeliveries(
+	ctx context.Context, limit int, lease time.Duration,
+) ([]models.WebhookDelivery, error) {
+	const method = "ClaimDeliveries"
+
+	res, err := r.pool.Query(ctx,
+		`UPDATE webhook_deliveries d SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM webhooks w
+		WHERE w.uuid = d.webhook_uuid AND d.uuid IN (
+			SELECT p.uuid FROM webhook_deliveries p JOIN webhooks pw ON pw.uuid = p.webhook_uuid
+			WHERE p.status = 'pending' AND p.next_attempt_at <= now() AND pw.enabled
+			ORDER BY p.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF p SKIP LOCKED
+		)
+		RETURNING d.uuid, d.webhook_uuid, d.event_id, d.event_type, d.payload, d.attempts, w.url, w.secret`,
+		limit, lease.Seconds())
+	if err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Query", "webhook_deliveries", err)
+	}
+	defer res.Close()
+
+	var deliveries []models.WebhookDelivery
+	for res.Next() {
+		d := models.WebhookDelivery{Status: models.DeliveryPending}
+		err = res.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.Attempts, &d.URL, &d.Secret)
+		if err != nil {
+			return nil, errorspkg.NewRepoFailedError(method, "Scan", "webhook_deliveries", err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err = res.Err(); err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Rows", "webhook_deliveries", err)
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt сохраняет результат попытки доставки и обновляет счётчик подряд
+// неудачных попыток вебхука. После disableAfter неудач вебхук выключается.
+// Возвращает true, если вебхук был выключен этой попыткой.
+func (r *WebhookRepo) RecordAttempt(
+	ctx context.Context, d *models.WebhookDelivery, disableAfter int,
+) (bool, error) {
+	const method = "RecordAttempt"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, errorspkg.NewRepoFailedError(method, "Begin", "webhook_deliveries", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	_, err = tx.Exec(ctx,
+		`UPDATE webhook_deliveries SET
+		status = $1,
+		attempts = $2,
+		response_code = $3,
+		last_error = $4,
+		next_attempt_at = COALESCE($5, next_attempt_at),
+		delivered_at = $6
+		WHERE uuid = $7`,
+		d.Status, d.Attempts, d.ResponseCode, d.LastError, d.NextAttemptAt, d.DeliveredAt, d.ID)
+	if err != nil {
+		return false, errorspkg.NewRepoFailedError(method, "Exec", "webhook_deliveries", err)
+	}
+
+	disabled := false
+	if d.Status == models.DeliveryDelivered {
+		_, err = tx.Exec(ctx, "UPDATE webhooks SET failures = 0 WHERE uuid = $1", d.WebhookID)
+	} else {
+		err = tx.QueryRow(ctx,
+			`UPDATE webhooks SET
+			failures = failures + 1,
+			enabled = enabled AND failures + 1 < $2,
+			disabled_reason = CASE WHEN enabled AND failures + 1 >= $2 THEN $3 ELSE disabled_reason END
+			WHERE uuid = $1
+			RETURNING NOT enabled AND failures = $2`,
+			d.WebhookID, disableAfter, fmt.Sprintf("%d consecutive failed deliveries: %s", disableAfter, d.LastError),
+		).Scan(&disabled)
+	}
+
+	if err != nil {
+		return false, errorspkg.NewRepoFailedError(method, "Exec", "webhooks", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, errorspkg.NewRepoFailedError(method, "Commit", "webhook_deliveries", err)
+	}
+
+	return disabled, nil
+}
+
+// SelectDeliveries возвращает последние доставки вебхука, новые первыми.
+func (r *WebhookRepo) SelectDeliveries(
+	ctx context.Context, webhookUUID string, limit int,
+) ([]models.WebhookDelivery, error) {
+	const method = "SelectDeliveries"
+
+	res, err := r.pool.Query(ctx,
+		`SELECT uuid, webhook_uuid, event_id, event_type, status, attempts, response_code, last_error,
+		CASE WHEN status = 'pending' THEN next_attempt_at END, created_at, delivered_at
+		FROM webhook_deliveries WHERE webhook_uuid = $1
+		ORDER BY created_at DESC
+		LIMIT $2`,
+		webhookUUID, limit)
+	if err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Query", "webhook_deliveries", err)
+	}
+	defer res.Close()
+
+	var deliveries []models.WebhookDelivery
+	for res.Next() {
+		d := models.WebhookDelivery{}
+		err = res.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Status, &d.Attempts,
+			&d.ResponseCode, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt)
+		if err != nil {
+			return nil, errorspkg.NewRepoFailedError(method, "Scan", "webhook_deliveries", err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err = res.Err(); err != nil {
+		return nil, errorspkg.NewRepoFailedError(method, "Rows", "webhook_deliveries", err)
+	}
+
+	return deliveries, nil
+}
@@ -11,13 +11,72 @@ import (
 
 type ITodoTask interface {
 	InsertTask(ctx context.Context, task *models.Task) (string, error)
-	UpdateTask(ctx context.Context, task *models.Task) error
-	DeleteTask(ctx context.Context, uuid string) error
+	UpsertTasks(ctx context.Context, tasks []models.Task, replace bool) error
+	UpdateTask(ctx context.Context, task *models.Task, eventType string) error
+	DeleteTask(ctx context.Context, task *models.Task, eventType string) error
 	Select(ctx context.Context, selectConfig *models.SelectConfig) ([]models.Task, error)
+	SelectEach(ctx context.Context, selectConfig *models.SelectConfig, fn func(*models.Task) error) error
+	RollForward(ctx context.Context, before string, advance func(task *models.Task) []string) (int, bool, error)
+	SelectMissed(ctx context.Context, taskUUID string, limit int) ([]models.MissedTask, error)
+	SnoozeTask(ctx context.Context, task *models.Task) (bool, error)
+}
+
+type IView interface {
+	InsertView(ctx context.Context, view *models.View) (string, error)
+	UpdateView(ctx context.Context, view *models.View) (bool, error)
+	DeleteView(ctx context.Context, uuid string) error
+	SelectViews(ctx context.Context, uuid string) ([]models.View, error)
+}
+
+type IWebhook interface {
+	InsertWebhook(ctx context.Context, webhook *models.Webhook) (string, error)
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) (bool, error)
+	DeleteWebhook(ctx context.Context, uuid string) error
+	SelectWebhooks(ctx context.Context, uuid string) ([]models.Webhook, error)
+	EnqueueDeliveries(ctx context.Context, eventID, eventType string, payload []byte) (int, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, disableAfter int) (bool, error)
+	SelectDeliveries(ctx context.Context, webhookUUID string, limit int) ([]models.WebhookDelivery, error)
+}
+
+type IOutbox interface {
+	ProcessOutbox(ctx context.Context, limit int, fn func([]models.TaskEvent) error) (int, error)
+	ListenOutbox(ctx context.Context, fn func([]models.TaskEvent)) error
+}
+
+type IReminder interface {
+	InsertReminder(ctx context.Context, reminder *models.Reminder) (string, error)
+	DeleteReminder(ctx context.Context, uuid string) error
+	SelectReminders(ctx context.Context, taskUUID string) ([]models.Reminder, error)
+	ClaimReminders(ctx context.Context, limit int, lease time.Duration, timezone string) ([]models.Reminder, error)
+	RecordReminder(ctx context.Context, reminder *models.Reminder) error
+}
+
+type IDigest interface {
+	ClaimDigest(ctx context.Context, recipient, day string, lease time.Duration) (models.Digest, bool, error)
+	RecordDigest(ctx context.Context, digest *models.Digest, retryAfter time.Duration) error
+}
+
+type IFeed interface {
+	InitFeedToken(ctx context.Context, token string) (string, error)
+	ReplaceFeedToken(ctx context.Context, token string) error
+	SelectFeedToken(ctx context.Context) (string, bool, error)
+}
+
+type IHealth interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (int64, error)
 }
 
 type Repository struct {
 	TodoTask ITodoTask
+	View     IView
+	Webhook  IWebhook
+	Outbox   IOutbox
+	Reminder IReminder
+	Digest   IDigest
+	Feed     IFeed
+	Health   IHealth
 }
 
 func Ping(ctx context.Context, pinger *pgxpool.Pool, timeout time.Duration) error {
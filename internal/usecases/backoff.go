@@ -0,0 +1,18 @@
+package usecases
+
+import (
+	"math"
+	mathrand "math/rand/v2"
+	"time"
+)
+
+// backoff — экспоненциальная задержка перед попыткой attempts+1: base, 2·base, 4·base
+// и так далее, не больше limit, с разбросом до 10%.
+func backoff(attempts int, base, limit time.Duration) time.Duration {
+	delay := time.Duration(float64(base) * math.Pow(2, float64(attempts-1)))
+	if delay <= 0 || delay > limit {
+		delay = limit
+	}
+
+	return delay + time.Duration(mathrand.Int64N(int64(delay)/10+1))
+}
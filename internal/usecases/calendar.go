@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/tracing"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+	"github.com/sater-151/todo-list/internal/utils/datevalidating"
+	"github.com/sater-151/todo-list/internal/utils/selectconfig"
+)
+
+const maxCalendarDays = 366
+
+// Calendar разворачивает повторяющиеся задачи в отдельные вхождения в интервале
+// [from, to] и группирует их по дням.
+func (s *TodoTask) Calendar(ctx context.Context, calendarRange *models.CalendarRange) (*models.Calendar, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.Calendar")
+	defer span.End()
+
+	from, to, err := checkCalendarRange(calendarRange)
+	if err != nil {
+		logctx.Warn(ctx, err.Error())
+
+		return nil, errorspkg.ErrBadRequest
+	}
+
+	selectConfig := selectconfig.Default()
+	selectConfig.DateTo = calendarRange.To
+	selectConfig.Limit = ""
+
+	tasks, err := s.todoTaskRepo.Select(ctx, selectConfig)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	byDay := make(map[string][]models.Occurrence)
+	for _, task := range tasks {
+		var dates []time.Time
+		if task.SnoozedFrom != "" {
+			dates, err = datevalidating.SnoozedOccurrences(task.Date, task.SnoozedFrom, task.Repeat, from, to)
+		} else {
+			dates, err = datevalidating.Occurrences(task.Date, task.Repeat, from, to)
+		}
+
+		if err != nil {
+			logctx.Warn(ctx, "skipping task in calendar", slog.String("id", task.ID), slog.String("error", err.Error()))
+
+			continue
+		}
+
+		for _, date := range dates {
+			day := date.Format("20060102")
+			byDay[day] = append(byDay[day], models.Occurrence{
+				SeriesID: task.ID,
+				Date:     day,
+				Title:    task.Title,
+				Comment:  task.Comment,
+				Repeat:   task.Repeat,
+			})
+		}
+	}
+
+	calendar := &models.Calendar{
+		From: calendarRange.From,
+		To:   calendarRange.To,
+		Days: make([]models.CalendarDay, 0, len(byDay)),
+	}
+
+	for day, occurrences := range byDay {
+		calendar.Days = append(calendar.Days, models.CalendarDay{Date: day, Occurrences: occurrences})
+	}
+
+	sort.Slice(calendar.Days, func(i, j int) bool {
+		return calendar.Days[i].Date < calendar.Days[j].Date
+	})
+
+	return calendar, nil
+}
+
+func checkCalendarRange(calendarRange *models.CalendarRange) (time.Time, time.Time, error) {
+	if err := validate.Struct(calendarRange); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	from, err := time.Parse("20060102", calendarRange.From)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	to, err := time.Parse("20060102", calendarRange.To)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if to.Before(from) || to.Sub(from) > maxCalendarDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("calendar range [%s, %s] must be non-empty and at most %d days",
+			calendarRange.From, calendarRange.To, maxCalendarDays)
+	}
+
+	return from, to, nil
+}
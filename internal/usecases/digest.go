@@ -0,0 +1,256 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/notifier"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+	"github.com/sater-151/todo-list/internal/utils/digest"
+	"github.com/sater-151/todo-list/internal/utils/selectconfig"
+)
+
+const (
+	digestLease      = 5 * time.Minute
+	digestRetryAfter = 10 * time.Minute
+)
+
+type (
+	IDigestRepo interface {
+		ClaimDigest(ctx context.Context, recipient, day string, lease time.Duration) (models.Digest, bool, error)
+		RecordDigest(ctx context.Context, record *models.Digest, retryAfter time.Duration) error
+	}
+)
+
+type (
+	DigestDependencies struct {
+		DigestRepo   IDigestRepo    `validate:"required"`
+		TodoTaskRepo ITodoTaskRepo  `validate:"required"`
+		Notifier     INotifier      `validate:"required"`
+		Location     *time.Location `validate:"required"`
+		SendAt       string         `validate:"required,datetime=15:04"`
+		Recipients   []string       `validate:"dive,email"`
+		Limit        int            `validate:"gt=0"`
+		Timeout      time.Duration  `validate:"gt=0"`
+		MaxAttempts  int            `validate:"gt=0"`
+	}
+
+	// DigestSettings — настройки сводки, которые можно менять без перезапуска.
+	DigestSettings struct {
+		SendAt      string        `validate:"required,datetime=15:04"`
+		Recipients  []string      `validate:"dive,email"`
+		Limit       int           `validate:"gt=0"`
+		Timeout     time.Duration `validate:"gt=0"`
+		MaxAttempts int           `validate:"gt=0"`
+	}
+
+	// digestSettings — DigestSettings с разобранным временем отправки.
+	digestSettings struct {
+		DigestSettings
+		sendAt time.Duration
+	}
+
+	Digest struct {
+		digestRepo   IDigestRepo
+		todoTaskRepo ITodoTaskRepo
+		notifier     INotifier
+		location     *time.Location
+		settings     atomic.Pointer[digestSettings]
+	}
+)
+
+func NewDigest(d *DigestDependencies) (*Digest, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("usecases.NewDigest", d, err)
+	}
+
+	s := &Digest{
+		digestRepo:   d.DigestRepo,
+		todoTaskRepo: d.TodoTaskRepo,
+		notifier:     d.Notifier,
+		location:     d.Location,
+	}
+
+	err := s.SetSettings(&DigestSettings{
+		SendAt:      d.SendAt,
+		Recipients:  d.Recipients,
+		Limit:       d.Limit,
+		Timeout:     d.Timeout,
+		MaxAttempts: d.MaxAttempts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// SetSettings заменяет настройки сводки. Уже начатая отправка дорабатывает
+// со старыми настройками.
+func (s *Digest) SetSettings(settings *DigestSettings) error {
+	if err := validate.Struct(settings); err != nil {
+		return errorspkg.NewValidationError("usecases.Digest.SetSettings", settings, err)
+	}
+
+	sendAt, err := time.Parse("15:04", settings.SendAt)
+	if err != nil {
+		return errorspkg.NewValidationError("usecases.Digest.SetSettings", settings, err)
+	}
+
+	s.settings.Store(&digestSettings{
+		DigestSettings: *settings,
+		sendAt:         time.Duration(sendAt.Hour())*time.Hour + time.Duration(sendAt.Minute())*time.Minute,
+	})
+
+	return nil
+}
+
+// SendDue отправляет сводку за текущий день тем получателям, которым она ещё
+// не отправлена, если время отправки уже наступило. Возвращает число отправок.
+func (s *Digest) SendDue(ctx context.Context) (int, error) {
+	settings := s.settings.Load()
+
+	if len(settings.Recipients) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now().In(s.location)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location)
+
+	if now.Sub(midnight) < settings.sendAt {
+		return 0, nil
+	}
+
+	day := midnight.Format("20060102")
+
+	var (
+		data *digest.Data
+		sent int
+	)
+
+	for _, recipient := range settings.Recipients {
+		d, ok, err := s.digestRepo.ClaimDigest(ctx, recipient, day, digestLease)
+		if err != nil {
+			return sent, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		// Сводка собирается один раз и только если кому-то её нужно отправить.
+		if data == nil {
+			if data, err = s.collect(ctx, midnight, settings.Limit); err != nil {
+				return sent, err
+			}
+		}
+
+		s.send(ctx, &d, data, settings)
+		sent++
+	}
+
+	return sent, nil
+}
+
+func (s *Digest) collect(ctx context.Context, day time.Time, limit int) (*digest.Data, error) {
+	today := day.Format("20060102")
+	tomorrow := day.AddDate(0, 0, 1).Format("20060102")
+	repeating := true
+
+	overdue := digestSelectConfig(limit)
+	overdue.DateBefore = today
+
+	current := digestSelectConfig(limit)
+	current.Date = today
+
+	next := digestSelectConfig(limit)
+	next.Date = tomorrow
+	next.Repeating = &repeating
+
+	data := &digest.Data{Day: today}
+
+	for _, q := range []struct {
+		config *models.SelectConfig
+		dest   *[]models.Task
+	}{
+		{overdue, &data.Overdue},
+		{current, &data.Today},
+		{next, &data.Tomorrow},
+	} {
+		tasks, err := s.todoTaskRepo.Select(ctx, q.config)
+		if err != nil {
+			return nil, err
+		}
+
+		*q.dest = tasks
+	}
+
+	return data, nil
+}
+
+func digestSelectConfig(limit int) *models.SelectConfig {
+	selectConfig := selectconfig.Default()
+	selectConfig.Limit = strconv.Itoa(limit)
+
+	return selectConfig
+}
+
+func (s *Digest) send(ctx context.Context, d *models.Digest, data *digest.Data, settings *digestSettings) {
+	d.Attempts++
+	d.LastError = ""
+
+	err := s.deliver(ctx, d.Recipient, data, settings.Timeout)
+
+	now := time.Now()
+
+	switch {
+	case err == nil && data.Empty():
+		d.Status = models.DigestSkipped
+	case err == nil:
+		d.Status = models.DigestSent
+		d.SentAt = &now
+	case d.Attempts >= settings.MaxAttempts || errors.Is(err, notifier.ErrNotDelivered):
+		d.Status = models.DigestFailed
+		d.LastError = err.Error()
+	default:
+		d.Status = models.DigestPending
+		d.LastError = err.Error()
+	}
+
+	if err != nil {
+		logctx.Warn(ctx, "digest not sent", slog.String("recipient", d.Recipient), slog.String("error", err.Error()))
+	}
+
+	if err = s.digestRepo.RecordDigest(ctx, d, digestRetryAfter); err != nil {
+		logctx.Error(ctx, err.Error())
+	}
+}
+
+// deliver отправляет сводку. Пустая сводка не отправляется.
+func (s *Digest) deliver(ctx context.Context, recipient string, data *digest.Data, timeout time.Duration) error {
+	if data.Empty() {
+		return nil
+	}
+
+	text, html, err := digest.Render(data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return s.notifier.Notify(ctx, &models.Notification{
+		To:      recipient,
+		Subject: data.Title(),
+		Text:    text,
+		HTML:    html,
+	})
+}
@@ -0,0 +1,103 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+const feedTokenBytes = 32
+
+type (
+	IFeedRepo interface {
+		InitFeedToken(ctx context.Context, token string) (string, error)
+		ReplaceFeedToken(ctx context.Context, token string) error
+		SelectFeedToken(ctx context.Context) (string, bool, error)
+	}
+)
+
+type (
+	FeedDependencies struct {
+		FeedRepo IFeedRepo `validate:"required"`
+	}
+
+	// Feed управляет секретом ссылки на календарный фид. Секрет случайный и хранится
+	// в базе, поэтому не зависит от пароля и меняется отдельно от него.
+	Feed struct {
+		feedRepo IFeedRepo
+	}
+)
+
+func NewFeed(d *FeedDependencies) (*Feed, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("usecases.NewFeed", d, err)
+	}
+
+	return &Feed{
+		feedRepo: d.FeedRepo,
+	}, nil
+}
+
+// Token возвращает секрет фида и создаёт его при первом обращении.
+func (s *Feed) Token(ctx context.Context) (string, error) {
+	token, err := newFeedToken()
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return "", errorspkg.ErrInternalError
+	}
+
+	token, err = s.feedRepo.InitFeedToken(ctx, token)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return "", errorspkg.ErrInternalError
+	}
+
+	return token, nil
+}
+
+// RegenerateToken заменяет секрет фида новым; прежние ссылки перестают работать.
+func (s *Feed) RegenerateToken(ctx context.Context) (string, error) {
+	token, err := newFeedToken()
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return "", errorspkg.ErrInternalError
+	}
+
+	if err := s.feedRepo.ReplaceFeedToken(ctx, token); err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return "", errorspkg.ErrInternalError
+	}
+
+	return token, nil
+}
+
+// CheckToken сообщает, совпадает ли token с секретом фида. Пока секрет не создан,
+// не подходит никакой token.
+func (s *Feed) CheckToken(ctx context.Context, token string) (bool, error) {
+	stored, ok, err := s.feedRepo.SelectFeedToken(ctx)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return false, errorspkg.ErrInternalError
+	}
+
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(stored)) == 1, nil
+}
+
+func newFeedToken() (string, error) {
+	token := make([]byte, feedTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
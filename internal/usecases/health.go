@@ -0,0 +1,140 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+const (
+	healthCheckPostgres   = "postgres"
+	healthCheckMigrations = "migrations"
+	healthCheckShutdown   = "shutdown"
+)
+
+type (
+	IHealthRepo interface {
+		Ping(ctx context.Context) error
+		MigrationVersion(ctx context.Context) (int64, error)
+	}
+)
+
+type (
+	// HealthDependencies: Migration — номер последней миграции в сборке, Timeout
+	// ограничивает каждую проверку готовности.
+	HealthDependencies struct {
+		HealthRepo IHealthRepo   `validate:"required"`
+		Migration  int64         `validate:"gt=0"`
+		Timeout    time.Duration `validate:"gt=0"`
+	}
+
+	Health struct {
+		healthRepo IHealthRepo
+		migration  int64
+		timeout    time.Duration
+		draining   atomic.Bool
+	}
+)
+
+func NewHealth(d *HealthDependencies) (*Health, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("usecases.NewHealth", d, err)
+	}
+
+	return &Health{
+		healthRepo: d.HealthRepo,
+		migration:  d.Migration,
+		timeout:    d.Timeout,
+	}, nil
+}
+
+// Drain переводит сервис в неготовность перед остановкой, чтобы балансировщик
+// перестал направлять на него запросы.
+func (s *Health) Drain() {
+	s.draining.Store(true)
+}
+
+// Ready выполняет проверки готовности параллельно и возвращает их результаты.
+// Сервис готов, если прошли все проверки.
+func (s *Health) Ready(ctx context.Context) (*models.Readiness, bool) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	checks := map[string]func(ctx context.Context) error{
+		healthCheckPostgres:   s.healthRepo.Ping,
+		healthCheckMigrations: s.checkMigrations,
+		healthCheckShutdown:   s.checkShutdown,
+	}
+
+	readiness := &models.Readiness{
+		Status: models.HealthOK,
+		Checks: make(map[string]models.HealthCheck, len(checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			result := models.HealthCheck{Status: models.HealthOK}
+
+			if err := check(ctx); err != nil {
+				result.Status = models.HealthFailed
+				result.Error = err.Error()
+			}
+
+			result.Duration = time.Since(start).String()
+
+			mu.Lock()
+			readiness.Checks[name] = result
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	for _, check := range readiness.Checks {
+		if check.Status != models.HealthOK {
+			readiness.Status = models.HealthFailed
+		}
+	}
+
+	if s.draining.Load() {
+		readiness.Status = models.HealthDraining
+	}
+
+	return readiness, readiness.Status == models.HealthOK
+}
+
+func (s *Health) checkMigrations(ctx context.Context) error {
+	version, err := s.healthRepo.MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version < s.migration {
+		return fmt.Errorf("database is at migration %d, want %d", version, s.migration)
+	}
+
+	return nil
+}
+
+func (s *Health) checkShutdown(_ context.Context) error {
+	if s.draining.Load() {
+		return fmt.Errorf("service is shutting down")
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/tracing"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+	"github.com/sater-151/todo-list/internal/utils/datevalidating"
+	"github.com/sater-151/todo-list/internal/utils/selectconfig"
+)
+
+const maxImportTasks = 10000
+
+// ImportTasks проверяет задачи так же, как AddTask, и сохраняет корректные
+// в одной транзакции. Задачи с ID обновляются, если уже существуют. В режиме
+// replace все остальные задачи удаляются. Некорректные задачи пропускаются
+// и попадают в Problems вслед за problems, найденными при разборе файла.
+// Replace с проблемами не выполняется: задачи из отклонённых строк были бы
+// удалены; тогда возвращается результат вместе с ErrBadRequest. В режиме DryRun
+// ничего не сохраняется. При Restore даты и откладывание задач сохраняются
+// как есть, иначе прошедшие даты переносятся.
+func (s *TodoTask) ImportTasks(
+	ctx context.Context, tasks []models.Task, problems []models.ImportProblem, options *models.ImportOptions,
+) (*models.ImportResult, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.ImportTasks")
+	defer span.End()
+
+	if err := validate.Struct(options); err != nil {
+		logctx.Warn(ctx, err.Error())
+
+		return nil, errorspkg.ErrBadRequest
+	}
+
+	if len(tasks) > maxImportTasks {
+		logctx.Warn(ctx, "import: too many tasks", "count", len(tasks), "max", maxImportTasks)
+
+		return nil, errorspkg.ErrBadRequest
+	}
+
+	result := &models.ImportResult{
+		Mode:     options.Mode,
+		DryRun:   options.DryRun,
+		Tasks:    make([]models.Task, 0, len(tasks)),
+		Problems: append([]models.ImportProblem(nil), problems...),
+	}
+
+	if result.Mode == "" {
+		result.Mode = models.ImportModeMerge
+	}
+
+	for i := range tasks {
+		task, err := checkImportTask(&tasks[i], options.Restore)
+		if err != nil {
+			result.Problems = append(result.Problems, models.ImportProblem{
+				UID:    task.ID,
+				Title:  task.Title,
+				Reason: err.Error(),
+			})
+
+			continue
+		}
+
+		result.Tasks = append(result.Tasks, *task)
+	}
+
+	replace := result.Mode == models.ImportModeReplace
+	if replace && !options.DryRun && len(result.Problems) > 0 {
+		logctx.Warn(ctx, "import: replace refused, file has invalid rows", "problems", len(result.Problems))
+
+		return result, errorspkg.ErrBadRequest
+	}
+
+	// Пустой импорт в режиме replace удалил бы все задачи, скорее всего по ошибке.
+	if options.DryRun || len(result.Tasks) == 0 {
+		return result, nil
+	}
+
+	if err := s.todoTaskRepo.UpsertTasks(ctx, result.Tasks, replace); err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	result.Imported = len(result.Tasks)
+
+	// Массовое изменение передаётся одним событием reset: клиентам проще перечитать список.
+	s.outbox.Notify()
+
+	return result, nil
+}
+
+func checkImportTask(task *models.Task, restore bool) (*models.Task, error) {
+	if task.ID != "" {
+		if _, err := uuid.Parse(task.ID); err != nil {
+			return task, fmt.Errorf("invalid id [%s]: %w", task.ID, err)
+		}
+	}
+
+	if restore {
+		return datevalidating.CheckStoredTask(task)
+	}
+
+	task.SnoozedFrom = ""
+
+	return datevalidating.CheckTask(task)
+}
+
+// ExportTasks передаёт все задачи в fn по одной в порядке дат.
+func (s *TodoTask) ExportTasks(ctx context.Context, fn func(*models.Task) error) error {
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.ExportTasks")
+	defer span.End()
+
+	selectConfig := selectconfig.Default()
+	selectConfig.Limit = ""
+
+	if err := s.todoTaskRepo.SelectEach(ctx, selectConfig, fn); err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return errorspkg.ErrInternalError
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/usecases"
+)
+
+type upsertCall struct {
+	tasks   []models.Task
+	replace bool
+}
+
+type importRepo struct {
+	usecases.ITodoTaskRepo
+
+	upserts []upsertCall
+}
+
+func (r *importRepo) UpsertTasks(_ context.Context, tasks []models.Task, replace bool) error {
+	r.upserts = append(r.upserts, upsertCall{tasks: tasks, replace: replace})
+
+	return nil
+}
+
+type nopOutbox struct{}
+
+func (nopOutbox) Notify() {}
+
+type nopMetrics struct{}
+
+func (nopMetrics) CountTaskOperation(string, int) {}
+
+func (nopMetrics) NextDateFailed() {}
+
+func TestImportTasks(t *testing.T) {
+	t.Parallel()
+
+	const (
+		liveID    = "0192f0c4-7d3a-7b6e-9c1d-2f4a5b6c7d8e"
+		invalidID = "0192f0c4-7d3a-7b6e-9c1d-2f4a5b6c7d8f"
+	)
+
+	valid := models.Task{ID: liveID, Date: "20260105", Title: "Report"}
+	// Строка без названия не проходит проверку.
+	invalid := models.Task{ID: invalidID, Date: "20260105"}
+	decodeProblem := models.ImportProblem{Title: "x 2026-01-05 done", Reason: "completed task"}
+
+	tests := []struct {
+		name         string
+		tasks        []models.Task
+		problems     []models.ImportProblem
+		options      models.ImportOptions
+		wantErr      error
+		wantProblems int
+		wantUpsert   bool
+	}{
+		{
+			name:       "replace without problems",
+			tasks:      []models.Task{valid},
+			options:    models.ImportOptions{Mode: models.ImportModeReplace, Restore: true},
+			wantUpsert: true,
+		},
+		{
+			name:         "replace with an invalid row is refused",
+			tasks:        []models.Task{valid, invalid},
+			options:      models.ImportOptions{Mode: models.ImportModeReplace, Restore: true},
+			wantErr:      errorspkg.ErrBadRequest,
+			wantProblems: 1,
+		},
+		{
+			name:         "replace with a row rejected by the decoder is refused",
+			tasks:        []models.Task{valid},
+			problems:     []models.ImportProblem{decodeProblem},
+			options:      models.ImportOptions{Mode: models.ImportModeReplace, Restore: true},
+			wantErr:      errorspkg.ErrBadRequest,
+			wantProblems: 1,
+		},
+		{
+			name:         "replace dry run reports problems",
+			tasks:        []models.Task{valid, invalid},
+			options:      models.ImportOptions{Mode: models.ImportModeReplace, DryRun: true, Restore: true},
+			wantProblems: 1,
+		},
+		{
+			name:         "merge skips invalid rows",
+			tasks:        []models.Task{valid, invalid},
+			problems:     []models.ImportProblem{decodeProblem},
+			options:      models.ImportOptions{Mode: models.ImportModeMerge, Restore: true},
+			wantProblems: 2,
+			wantUpsert:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &importRepo{}
+
+			s, err := usecases.NewTodoTask(&usecases.TodoTaskDependencies{
+				TodoTaskRepo: repo,
+				Outbox:       nopOutbox{},
+				Metrics:      nopMetrics{},
+			})
+			if err != nil {
+				t.Fatalf("NewTodoTask: %v", err)
+			}
+
+			tasks := append([]models.Task(nil), tt.tasks...)
+
+			result, err := s.ImportTasks(context.Background(), tasks, tt.problems, &tt.options)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ImportTasks() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if result == nil {
+				t.Fatal("ImportTasks() returned no result")
+			}
+
+			if len(result.Problems) != tt.wantProblems {
+				t.Errorf("problems = %+v, want %d", result.Problems, tt.wantProblems)
+			}
+
+			if !tt.wantUpsert {
+				if len(repo.upserts) != 0 || result.Imported != 0 {
+					t.Errorf("tasks were saved: upserts %+v, imported %d", repo.upserts, result.Imported)
+				}
+
+				return
+			}
+
+			if len(repo.upserts) != 1 {
+				t.Fatalf("UpsertTasks called %d times, want 1", len(repo.upserts))
+			}
+
+			call := repo.upserts[0]
+			if call.replace != (tt.options.Mode == models.ImportModeReplace) {
+				t.Errorf("UpsertTasks replace = %v", call.replace)
+			}
+
+			if len(call.tasks) != 1 || call.tasks[0].ID != liveID {
+				t.Errorf("UpsertTasks tasks = %+v, want only %s", call.tasks, liveID)
+			}
+		})
+	}
+}
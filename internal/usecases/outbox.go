@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+const outboxBatchSize = 100
+
+type (
+	IOutboxRepo interface {
+		ProcessOutbox(ctx context.Context, limit int, fn func([]models.TaskEvent) error) (int, error)
+		ListenOutbox(ctx context.Context, fn func([]models.TaskEvent)) error
+	}
+
+	// IEventSink получает события из outbox. Событие может прийти повторно,
+	// поэтому обработка должна быть идемпотентной по event.ID.
+	IEventSink interface {
+		HandleEvent(ctx context.Context, event models.TaskEvent) error
+	}
+)
+
+type (
+	// OutboxDependencies: Sinks получают каждое событие один раз на все экземпляры
+	// сервиса (очереди в базе), Broadcast — в каждом экземпляре (локальные подписчики).
+	OutboxDependencies struct {
+		OutboxRepo IOutboxRepo  `validate:"required"`
+		Sinks      []IEventSink `validate:"required,min=1,dive,required"`
+		Broadcast  []IEventSink `validate:"dive,required"`
+	}
+
+	Outbox struct {
+		outboxRepo IOutboxRepo
+		sinks      []IEventSink
+		broadcast  []IEventSink
+		wake       chan struct{}
+	}
+)
+
+func NewOutbox(d *OutboxDependencies) (*Outbox, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("usecases.NewOutbox", d, err)
+	}
+
+	return &Outbox{
+		outboxRepo: d.OutboxRepo,
+		sinks:      d.Sinks,
+		broadcast:  d.Broadcast,
+		wake:       make(chan struct{}, 1),
+	}, nil
+}
+
+// Notify сообщает, что в outbox появились события. Не блокируется.
+func (s *Outbox) Notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Wake возвращает канал, в который приходят сигналы Notify.
+func (s *Outbox) Wake() <-chan struct{} {
+	return s.wake
+}
+
+// Relay передаёт накопившиеся события получателям Sinks пачками. Пачка помечается
+// обработанной только после успешной обработки всеми получателями, поэтому при сбое
+// события будут доставлены повторно. Возвращает число переданных событий.
+func (s *Outbox) Relay(ctx context.Context) (int, error) {
+	total := 0
+
+	for {
+		n, err := s.outboxRepo.ProcessOutbox(ctx, outboxBatchSize, func(events []models.TaskEvent) error {
+			for i := range events {
+				for _, sink := range s.sinks {
+					if err := sink.HandleEvent(ctx, events[i]); err != nil {
+						return fmt.Errorf("handling event %s: %w", events[i].ID, err)
+					}
+				}
+			}
+
+			return nil
+		})
+		total += n
+
+		if err != nil || n < outboxBatchSize {
+			return total, err
+		}
+	}
+}
+
+// Listen передаёт новые события получателям Broadcast, пока не отменён ctx или не
+// оборвалась связь с базой. Ошибки получателей только записываются в журнал.
+func (s *Outbox) Listen(ctx context.Context) error {
+	return s.outboxRepo.ListenOutbox(ctx, func(events []models.TaskEvent) {
+		for i := range events {
+			for _, sink := range s.broadcast {
+				if err := sink.HandleEvent(ctx, events[i]); err != nil {
+					logctx.Warn(ctx, "broadcasting event failed",
+						slog.String("event", events[i].ID), slog.String("error", err.Error()))
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/notifier"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+	"github.com/sater-151/todo-list/internal/utils/selectconfig"
+)
+
+const (
+	reminderBatchSize   = 50
+	reminderBackoffBase = time.Minute
+	reminderBackoffMax  = time.Hour
+)
+
+type (
+	IReminderRepo interface {
+		InsertReminder(ctx context.Context, reminder *models.Reminder) (string, error)
+		DeleteReminder(ctx context.Context, uuid string) error
+		SelectReminders(ctx context.Context, taskUUID string) ([]models.Reminder, error)
+		ClaimReminders(ctx context.Context, limit int, lease time.Duration, timezone string) ([]models.Reminder, error)
+		RecordReminder(ctx context.Context, reminder *models.Reminder) error
+	}
+
+	// INotifier доставляет уведомление пользователю, например письмом.
+	INotifier interface {
+		Notify(ctx context.Context, notification *models.Notification) error
+	}
+)
+
+type (
+	ReminderDependencies struct {
+		ReminderRepo IReminderRepo  `validate:"required"`
+		TodoTaskRepo ITodoTaskRepo  `validate:"required"`
+		Notifier     INotifier      `validate:"required"`
+		Location     *time.Location `validate:"required"`
+		Timeout      time.Duration  `validate:"gt=0"`
+		MaxAttempts  int            `validate:"gt=0"`
+	}
+
+	// ReminderSettings — настройки отправки, которые можно менять без перезапуска.
+	ReminderSettings struct {
+		Timeout     time.Duration `validate:"gt=0"`
+		MaxAttempts int           `validate:"gt=0"`
+	}
+
+	Reminder struct {
+		reminderRepo IReminderRepo
+		todoTaskRepo ITodoTaskRepo
+		notifier     INotifier
+		location     *time.Location
+		settings     atomic.Pointer[ReminderSettings]
+	}
+)
+
+func NewReminder(d *ReminderDependencies) (*Reminder, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("usecases.NewReminder", d, err)
+	}
+
+	s := &Reminder{
+		reminderRepo: d.ReminderRepo,
+		todoTaskRepo: d.TodoTaskRepo,
+		notifier:     d.Notifier,
+		location:     d.Location,
+	}
+	s.settings.Store(&ReminderSettings{Timeout: d.Timeout, MaxAttempts: d.MaxAttempts})
+
+	return s, nil
+}
+
+// SetSettings заменяет настройки отправки. Уже начатая пачка напоминаний
+// дорабатывает со старыми настройками.
+func (s *Reminder) SetSettings(settings *ReminderSettings) error {
+	if err := validate.Struct(settings); err != nil {
+		return errorspkg.NewValidationError("usecases.Reminder.SetSettings", settings, err)
+	}
+
+	s.settings.Store(settings)
+
+	return nil
+}
+
+func (s *Reminder) AddReminder(ctx context.Context, reminder *models.Reminder) (*models.Reminder, error) {
+	if err := validate.Struct(reminder); err != nil {
+		logctx.Warn(ctx, err.Error())
+
+		return nil, errorspkg.ErrBadRequest
+	}
+
+	selectConfig := selectconfig.Default()
+	selectConfig.ID = reminder.TaskID
+
+	tasks, err := s.todoTaskRepo.Select(ctx, selectConfig)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	if len(tasks) == 0 {
+		return nil, errorspkg.ErrNotFound
+	}
+
+	id, err := s.reminderRepo.InsertReminder(ctx, reminder)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	reminder.ID = id
+	reminder.Status = models.ReminderPending
+	reminder.Occurrence = ""
+	reminder.Attempts = 0
+	reminder.LastError = ""
+	reminder.SentAt = nil
+
+	return reminder, nil
+}
+
+func (s *Reminder) DeleteReminder(ctx context.Context, reminderUUID string) error {
+	if err := s.reminderRepo.DeleteReminder(ctx, reminderUUID); err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return errorspkg.ErrInternalError
+	}
+
+	return nil
+}
+
+// ListReminders возвращает напоминания задачи или все напоминания, если taskID пуст.
+func (s *Reminder) ListReminders(ctx context.Context, taskID string) ([]models.Reminder, error) {
+	if taskID != "" {
+		if _, err := uuid.Parse(taskID); err != nil {
+			logctx.Warn(ctx, err.Error())
+
+			return nil, errorspkg.ErrBadRequest
+		}
+	}
+
+	reminders, err := s.reminderRepo.SelectReminders(ctx, taskID)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	return reminders, nil
+}
+
+// SendDue отправляет напоминания, время которых пришло. Возвращает число
+// обработанных напоминаний.
+func (s *Reminder) SendDue(ctx context.Context) (int, error) {
+	settings := s.settings.Load()
+
+	reminders, err := s.reminderRepo.ClaimReminders(
+		ctx, reminderBatchSize, reminderBatchSize*settings.Timeout, s.location.String(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range reminders {
+		if ctx.Err() != nil {
+			return i, ctx.Err()
+		}
+
+		s.send(ctx, &reminders[i], settings)
+	}
+
+	return len(reminders), nil
+}
+
+func (s *Reminder) send(ctx context.Context, r *models.Reminder, settings *ReminderSettings) {
+	sendCtx, cancel := context.WithTimeout(ctx, settings.Timeout)
+	err := s.notifier.Notify(sendCtx, reminderNotification(r))
+	cancel()
+
+	now := time.Now()
+	r.Attempts++
+	r.LastError = ""
+	r.SentAt = nil
+
+	switch {
+	case err == nil:
+		r.Status = models.ReminderSent
+		r.SentAt = &now
+	// Без настроенной почты повторные попытки бессмысленны.
+	case r.Attempts >= settings.MaxAttempts || errors.Is(err, notifier.ErrNotDelivered):
+		r.Status = models.ReminderFailed
+		r.LastError = err.Error()
+	default:
+		next := now.Add(backoff(r.Attempts, reminderBackoffBase, reminderBackoffMax))
+		r.Status = models.ReminderPending
+		r.NextAttemptAt = &next
+		r.LastError = err.Error()
+	}
+
+	if err = s.reminderRepo.RecordReminder(ctx, r); err != nil {
+		logctx.Error(ctx, err.Error())
+	}
+}
+
+func reminderNotification(r *models.Reminder) *models.Notification {
+	date := r.Task.Date
+	if t, err := time.Parse("20060102", r.Task.Date); err == nil {
+		date = t.Format("2006-01-02")
+	}
+
+	var text strings.Builder
+
+	fmt.Fprintf(&text, "%s\n\nDate: %s\n", r.Task.Title, date)
+
+	if r.Task.Repeat != "" {
+		fmt.Fprintf(&text, "Repeat: %s\n", r.Task.Repeat)
+	}
+
+	if r.Task.Comment != "" {
+		fmt.Fprintf(&text, "\n%s\n", r.Task.Comment)
+	}
+
+	return &models.Notification{
+		To:      r.Email,
+		Subject: "Reminder: " + r.Task.Title,
+		Text:    text.String(),
+	}
+}
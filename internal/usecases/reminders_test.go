@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/notifier"
+)
+
+type fakeReminderRepo struct {
+	IReminderRepo
+
+	claimed  []models.Reminder
+	recorded []models.Reminder
+}
+
+func (r *fakeReminderRepo) ClaimReminders(context.Context, int, time.Duration, string) ([]models.Reminder, error) {
+	return r.claimed, nil
+}
+
+func (r *fakeReminderRepo) RecordReminder(_ context.Context, reminder *models.Reminder) error {
+	r.recorded = append(r.recorded, *reminder)
+
+	return nil
+}
+
+type fakeTodoTaskRepo struct {
+	ITodoTaskRepo
+}
+
+type fakeNotifier struct {
+	err  error
+	sent []models.Notification
+}
+
+func (n *fakeNotifier) Notify(_ context.Context, notification *models.Notification) error {
+	n.sent = append(n.sent, *notification)
+
+	return n.err
+}
+
+func TestReminderSendDue(t *testing.T) {
+	errSMTP := errors.New("451 try again later")
+
+	tests := []struct {
+		name        string
+		attempts    int
+		notifyErr   error
+		wantStatus  string
+		wantError   string
+		wantSent    bool
+		wantBackoff time.Duration
+	}{
+		{name: "sent", wantStatus: models.ReminderSent, wantSent: true},
+		{name: "sent after retries", attempts: 2, wantStatus: models.ReminderSent, wantSent: true},
+		{
+			name:        "first failure is retried",
+			notifyErr:   errSMTP,
+			wantStatus:  models.ReminderPending,
+			wantError:   errSMTP.Error(),
+			wantBackoff: reminderBackoffBase,
+		},
+		{
+			name:        "retry backs off",
+			attempts:    1,
+			notifyErr:   errSMTP,
+			wantStatus:  models.ReminderPending,
+			wantError:   errSMTP.Error(),
+			wantBackoff: 2 * reminderBackoffBase,
+		},
+		{
+			name:       "not delivered without SMTP fails at once",
+			notifyErr:  notifier.ErrNotDelivered,
+			wantStatus: models.ReminderFailed,
+			wantError:  notifier.ErrNotDelivered.Error(),
+		},
+		{
+			name:       "last attempt fails",
+			attempts:   2,
+			notifyErr:  errSMTP,
+			wantStatus: models.ReminderFailed,
+			wantError:  errSMTP.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReminderRepo{claimed: []models.Reminder{{
+				ID:        "0192f0c4-7d3a-7b6e-9c1d-2f4a5b6c7d8e",
+				Email:     "user@example.com",
+				Status:    models.ReminderPending,
+				Attempts:  tt.attempts,
+				LastError: "previous error",
+				Task:      &models.Task{Date: "20260105", Title: "Report", Repeat: "d 1"},
+			}}}
+			sender := &fakeNotifier{err: tt.notifyErr}
+
+			s, err := NewReminder(&ReminderDependencies{
+				ReminderRepo: repo,
+				TodoTaskRepo: fakeTodoTaskRepo{},
+				Notifier:     sender,
+				Location:     time.UTC,
+				Timeout:      time.Second,
+				MaxAttempts:  3,
+			})
+			if err != nil {
+				t.Fatalf("NewReminder: %v", err)
+			}
+
+			start := time.Now()
+
+			n, err := s.SendDue(context.Background())
+			if err != nil || n != 1 {
+				t.Fatalf("SendDue() = %d, %v, want 1, nil", n, err)
+			}
+
+			if len(sender.sent) != 1 || sender.sent[0].To != "user@example.com" {
+				t.Fatalf("notifications = %+v", sender.sent)
+			}
+
+			if len(repo.recorded) != 1 {
+				t.Fatalf("recorded %d reminders, want 1", len(repo.recorded))
+			}
+
+			got := repo.recorded[0]
+
+			if got.Status != tt.wantStatus || got.Attempts != tt.attempts+1 || got.LastError != tt.wantError {
+				t.Errorf("recorded status=%q attempts=%d error=%q, want %q %d %q",
+					got.Status, got.Attempts, got.LastError, tt.wantStatus, tt.attempts+1, tt.wantError)
+			}
+
+			if (got.SentAt != nil) != tt.wantSent {
+				t.Errorf("SentAt = %v, want set %v", got.SentAt, tt.wantSent)
+			}
+
+			if tt.wantBackoff == 0 {
+				return
+			}
+
+			if got.NextAttemptAt == nil {
+				t.Fatal("NextAttemptAt is not set for a retry")
+			}
+
+			delay := got.NextAttemptAt.Sub(start)
+			if delay < tt.wantBackoff || delay > tt.wantBackoff+tt.wantBackoff/10+time.Second {
+				t.Errorf("retry in %s, want about %s", delay, tt.wantBackoff)
+			}
+		})
+	}
+}
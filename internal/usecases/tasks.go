@@ -2,32 +2,69 @@ package usecases
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
-	"strings"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sater-151/todo-list/internal/models"
 	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/tracing"
 	"github.com/sater-151/todo-list/internal/pkg/validate"
 	"github.com/sater-151/todo-list/internal/utils/datevalidating"
+	"github.com/sater-151/todo-list/internal/utils/quickadd"
+	"github.com/sater-151/todo-list/internal/utils/selectconfig"
+)
+
+const (
+	maxMissedDates     = 100
+	defaultMissedLimit = 50
+	maxMissedLimit     = 500
+	maxSnoozeUnits     = 365
+
+	// taskOperationSnoozed — метка метрик для отложенных задач; у остальных
+	// операций метки совпадают с типами событий.
+	taskOperationSnoozed = "snoozed"
 )
 
 type (
 	ITodoTaskRepo interface {
 		InsertTask(ctx context.Context, task *models.Task) (string, error)
-		UpdateTask(ctx context.Context, task *models.Task) error
-		DeleteTask(ctx context.Context, uuid string) error
+		UpsertTasks(ctx context.Context, tasks []models.Task, replace bool) error
+		UpdateTask(ctx context.Context, task *models.Task, eventType string) error
+		DeleteTask(ctx context.Context, task *models.Task, eventType string) error
 		Select(ctx context.Context, selectConfig *models.SelectConfig) ([]models.Task, error)
+		SelectEach(ctx context.Context, selectConfig *models.SelectConfig, fn func(*models.Task) error) error
+		RollForward(ctx context.Context, before string, advance func(task *models.Task) []string) (int, bool, error)
+		SelectMissed(ctx context.Context, taskUUID string, limit int) ([]models.MissedTask, error)
+		SnoozeTask(ctx context.Context, task *models.Task) (bool, error)
+	}
+
+	// IOutboxNotifier будит доставку событий из outbox, не дожидаясь очередного опроса.
+	IOutboxNotifier interface {
+		Notify()
+	}
+
+	// ITaskMetrics учитывает операции с задачами в метриках.
+	ITaskMetrics interface {
+		CountTaskOperation(operation string, n int)
+		NextDateFailed()
 	}
 )
 
 type (
 	TodoTaskDependencies struct {
-		TodoTaskRepo ITodoTaskRepo `validate:"required"`
+		TodoTaskRepo ITodoTaskRepo   `validate:"required"`
+		Outbox       IOutboxNotifier `validate:"required"`
+		Metrics      ITaskMetrics    `validate:"required"`
 	}
 
 	TodoTask struct {
 		todoTaskRepo ITodoTaskRepo
+		outbox       IOutboxNotifier
+		metrics      ITaskMetrics
 	}
 )
 
@@ -38,59 +75,83 @@ func NewTodoTask(d *TodoTaskDependencies) (*TodoTask, error) {
 
 	return &TodoTask{
 		todoTaskRepo: d.TodoTaskRepo,
+		outbox:       d.Outbox,
+		metrics:      d.Metrics,
 	}, nil
 }
 
 func (s *TodoTask) AddTask(ctx context.Context, task *models.Task) (string, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.AddTask")
+	defer span.End()
+
 	task, err := datevalidating.CheckTask(task)
 	if err != nil {
-		slog.Error(err.Error())
+		logctx.Error(ctx, err.Error())
 
 		return "", errorspkg.ErrBadRequest
 	}
 
 	id, err := s.todoTaskRepo.InsertTask(ctx, task)
 	if err != nil {
-		slog.Error(err.Error())
+		logctx.Error(ctx, err.Error())
 
 		return "", errorspkg.ErrInternalError
 	}
 
+	task.ID = id
+	s.outbox.Notify()
+	s.metrics.CountTaskOperation(models.TaskEventCreated, 1)
+
 	return id, nil
 }
 
 func (s *TodoTask) UpdateTask(ctx context.Context, task *models.Task) error {
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.UpdateTask")
+	defer span.End()
+
 	task, err := datevalidating.CheckTask(task)
 	if err != nil {
-		slog.Error(err.Error())
+		logctx.Error(ctx, err.Error())
 
 		return errorspkg.ErrBadRequest
 	}
 
-	err = s.todoTaskRepo.UpdateTask(ctx, task)
+	err = s.todoTaskRepo.UpdateTask(ctx, task, models.TaskEventUpdated)
 	if err != nil {
-		slog.Error(err.Error())
+		logctx.Error(ctx, err.Error())
 
 		return errorspkg.ErrInternalError
 	}
 
+	s.outbox.Notify()
+	s.metrics.CountTaskOperation(models.TaskEventUpdated, 1)
+
 	return nil
 }
 
 func (s *TodoTask) DeleteTask(ctx context.Context, uuid string) error {
-	if err := s.todoTaskRepo.DeleteTask(ctx, uuid); err != nil {
-		slog.Error(err.Error())
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.DeleteTask")
+	defer span.End()
+
+	if err := s.todoTaskRepo.DeleteTask(ctx, &models.Task{ID: uuid}, models.TaskEventDeleted); err != nil {
+		logctx.Error(ctx, err.Error())
 
 		return errorspkg.ErrInternalError
 	}
 
+	s.outbox.Notify()
+	s.metrics.CountTaskOperation(models.TaskEventDeleted, 1)
+
 	return nil
 }
 
 func (s *TodoTask) Select(ctx context.Context, selectConfig *models.SelectConfig) ([]models.Task, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.Select")
+	defer span.End()
+
 	tasks, err := s.todoTaskRepo.Select(ctx, selectConfig)
 	if err != nil {
-		slog.Error(err.Error())
+		logctx.Error(ctx, err.Error())
 
 		return nil, errorspkg.ErrInternalError
 	}
@@ -99,63 +160,294 @@ func (s *TodoTask) Select(ctx context.Context, selectConfig *models.SelectConfig
 }
 
 func (s *TodoTask) TaskDone(ctx context.Context, selectConfig *models.SelectConfig) error {
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.TaskDone")
+	defer span.End()
+
 	tasks, err := s.todoTaskRepo.Select(ctx, selectConfig)
 	if err != nil {
-		slog.Error(err.Error())
+		logctx.Error(ctx, err.Error())
 
 		return errorspkg.ErrInternalError
 	}
 
+	if len(tasks) == 0 {
+		return errorspkg.ErrNotFound
+	}
+
 	task := tasks[0]
 	if task.Repeat == "" {
-		err = s.todoTaskRepo.DeleteTask(ctx, selectConfig.ID)
+		err = s.todoTaskRepo.DeleteTask(ctx, &task, models.TaskEventDone)
 		if err != nil {
-			slog.Error(err.Error())
+			logctx.Error(ctx, err.Error())
 
 			return errorspkg.ErrInternalError
 		}
 
+		s.outbox.Notify()
+		s.metrics.CountTaskOperation(models.TaskEventDone, 1)
+
 		return nil
 	}
 
-	task.Date, err = datevalidating.NextDate(time.Now(), task.Date, task.Repeat)
+	now := time.Now()
+
+	// Отложенная задача продолжает исходное расписание, но не раньше даты, на которую
+	// её отложили: так же её показывают календарь и ICS.
+	switch {
+	case task.SnoozedFrom != "" && task.Date > now.Format("20060102"):
+		task.Date, err = datevalidating.ResumeDate(task.Date, task.SnoozedFrom, task.Repeat)
+	case task.SnoozedFrom != "":
+		task.Date, err = datevalidating.NextDate(now, task.SnoozedFrom, task.Repeat)
+	default:
+		task.Date, err = datevalidating.NextDate(now, task.Date, task.Repeat)
+	}
+
 	if err != nil {
-		slog.Error(err.Error())
+		logctx.Error(ctx, err.Error())
+		s.metrics.NextDateFailed()
 
 		return errorspkg.ErrBadRequest
 	}
 
-	if err := s.todoTaskRepo.UpdateTask(ctx, &task); err != nil {
-		slog.Error(err.Error())
+	if err := s.todoTaskRepo.UpdateTask(ctx, &task, models.TaskEventAdvanced); err != nil {
+		logctx.Error(ctx, err.Error())
 
 		return errorspkg.ErrInternalError
 	}
 
+	s.outbox.Notify()
+	s.metrics.CountTaskOperation(models.TaskEventDone, 1)
+
 	return nil
 }
 
 func (s *TodoTask) GetListTask(ctx context.Context, selectConfig *models.SelectConfig) ([]models.Task, error) {
-	if selectConfig.Search != "" {
-		date := strings.Split(selectConfig.Search, ".")
-		if len(date) == 3 {
-			var d string
-			for i := 2; i >= 0; i-- {
-				d += date[i]
-			}
-			_, err := time.Parse("20060102", d)
-			if err == nil {
-				selectConfig.Search = ""
-				selectConfig.Date = d
-			}
-		}
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.GetListTask")
+	defer span.End()
+
+	if date, ok := datevalidating.ParseDotDate(selectConfig.Search); ok {
+		selectConfig.Search = ""
+		selectConfig.Date = date
 	}
 
 	listTask, err := s.todoTaskRepo.Select(ctx, selectConfig)
 	if err != nil {
-		slog.Error(err.Error())
+		logctx.Error(ctx, err.Error())
 
 		return nil, errorspkg.ErrInternalError
 	}
 
 	return listTask, err
 }
+
+// ParseQuickTask разбирает текст быстрого добавления в задачу без сохранения,
+// чтобы клиент мог показать её пользователю на подтверждение.
+func (s *TodoTask) ParseQuickTask(ctx context.Context, text string) (*models.Task, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.ParseQuickTask")
+	defer span.End()
+
+	task, err := quickadd.Parse(text, time.Now())
+	if err != nil {
+		logctx.Warn(ctx, err.Error())
+
+		return nil, errorspkg.ErrBadRequest
+	}
+
+	task, err = datevalidating.CheckTask(task)
+	if err != nil {
+		logctx.Warn(ctx, err.Error())
+
+		return nil, errorspkg.ErrBadRequest
+	}
+
+	return task, nil
+}
+
+// RollForward переносит просроченные повторяющиеся задачи на ближайшую дату не раньше
+// сегодняшней и записывает пропущенные даты. Возвращает число перенесённых задач.
+func (s *TodoTask) RollForward(ctx context.Context) (int, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.RollForward")
+	defer span.End()
+
+	now := time.Now()
+	today := now.Format("20060102")
+
+	count, locked, err := s.todoTaskRepo.RollForward(ctx, today, func(task *models.Task) []string {
+		missed, err := rollForward(task, today, now)
+		if err != nil {
+			logctx.Warn(ctx, "task not rolled forward", slog.String("task", task.ID), slog.String("error", err.Error()))
+			s.metrics.NextDateFailed()
+		}
+
+		return missed
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if !locked {
+		logctx.Debug(ctx, "roll forward is running on another instance")
+	}
+
+	if count > 0 {
+		s.outbox.Notify()
+		s.metrics.CountTaskOperation(models.TaskEventAdvanced, count)
+	}
+
+	return count, nil
+}
+
+// rollForward переводит дату задачи на первую дату правила не раньше today и
+// возвращает пропущенные даты. Записываются не больше maxMissedDates первых дат.
+func rollForward(task *models.Task, today string, now time.Time) ([]string, error) {
+	var missed []string
+
+	date := task.Date
+	for date < today {
+		missed = append(missed, date)
+
+		day, err := time.Parse("20060102", date)
+		if err != nil {
+			return nil, err
+		}
+
+		// Остальные пропущенные даты не записываются: задача сразу переносится
+		// на ближайшую дату после вчерашней.
+		if len(missed) == maxMissedDates {
+			day = now.AddDate(0, 0, -1)
+		}
+
+		// Отложенная задача после пропущенной даты возвращается к исходному расписанию.
+		from := date
+		if len(missed) == 1 && task.SnoozedFrom != "" {
+			from = task.SnoozedFrom
+		}
+
+		if date, err = datevalidating.NextDate(day, from, task.Repeat); err != nil {
+			return nil, err
+		}
+	}
+
+	task.Date = date
+
+	return missed, nil
+}
+
+// ListMissed возвращает пропущенные даты задачи или всех задач, если taskID пуст.
+func (s *TodoTask) ListMissed(ctx context.Context, taskID string, limit int) ([]models.MissedTask, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.ListMissed")
+	defer span.End()
+
+	if taskID != "" {
+		if _, err := uuid.Parse(taskID); err != nil {
+			logctx.Warn(ctx, err.Error())
+
+			return nil, errorspkg.ErrBadRequest
+		}
+	}
+
+	if limit <= 0 {
+		limit = defaultMissedLimit
+	}
+
+	missed, err := s.todoTaskRepo.SelectMissed(ctx, taskID, min(limit, maxMissedLimit))
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	return missed, nil
+}
+
+// SnoozeTask откладывает задачу на дату или на срок, не меняя правило повторения.
+// Срок отсчитывается от даты задачи, а у просроченной задачи — от сегодняшнего дня.
+func (s *TodoTask) SnoozeTask(ctx context.Context, snooze *models.Snooze) (*models.Task, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "TodoTask.SnoozeTask")
+	defer span.End()
+
+	if err := validate.Struct(snooze); err != nil {
+		logctx.Warn(ctx, err.Error())
+
+		return nil, errorspkg.ErrBadRequest
+	}
+
+	if (snooze.Until == "") == (snooze.For == "") {
+		logctx.Warn(ctx, "snooze: exactly one of until and for is required")
+
+		return nil, errorspkg.ErrBadRequest
+	}
+
+	today := time.Now().Format("20060102")
+	task := &models.Task{ID: snooze.ID, Date: snooze.Until}
+
+	if snooze.For != "" {
+		selectConfig := selectconfig.Default()
+		selectConfig.ID = snooze.ID
+
+		tasks, err := s.todoTaskRepo.Select(ctx, selectConfig)
+		if err != nil {
+			logctx.Error(ctx, err.Error())
+
+			return nil, errorspkg.ErrInternalError
+		}
+
+		if len(tasks) == 0 {
+			return nil, errorspkg.ErrNotFound
+		}
+
+		if task.Date, err = snoozeDate(max(tasks[0].Date, today), snooze.For); err != nil {
+			logctx.Warn(ctx, err.Error())
+
+			return nil, errorspkg.ErrBadRequest
+		}
+	}
+
+	if task.Date < today {
+		logctx.Warn(ctx, "snooze: date is in the past", slog.String("date", task.Date))
+
+		return nil, errorspkg.ErrBadRequest
+	}
+
+	found, err := s.todoTaskRepo.SnoozeTask(ctx, task)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	if !found {
+		return nil, errorspkg.ErrNotFound
+	}
+
+	s.outbox.Notify()
+	s.metrics.CountTaskOperation(taskOperationSnoozed, 1)
+
+	return task, nil
+}
+
+// snoozeDate прибавляет к дате срок вида <N>d, <N>w или <N>m.
+func snoozeDate(date, period string) (string, error) {
+	t, err := time.Parse("20060102", date)
+	if err != nil {
+		return "", err
+	}
+
+	n, err := strconv.Atoi(period[:len(period)-1])
+	if err != nil || n <= 0 || n > maxSnoozeUnits {
+		return "", fmt.Errorf("invalid snooze period [%s]", period)
+	}
+
+	switch period[len(period)-1] {
+	case 'd':
+		t = t.AddDate(0, 0, n)
+	case 'w':
+		t = t.AddDate(0, 0, 7*n)
+	case 'm':
+		t = t.AddDate(0, n, 0)
+	default:
+		return "", fmt.Errorf("invalid snooze period [%s]", period)
+	}
+
+	return t.Format("20060102"), nil
+}
@@ -0,0 +1,118 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/usecases"
+)
+
+type taskRepo struct {
+	usecases.ITodoTaskRepo
+
+	tasks   []models.Task
+	updated []models.Task
+}
+
+func (r *taskRepo) Select(context.Context, *models.SelectConfig) ([]models.Task, error) {
+	return r.tasks, nil
+}
+
+func (r *taskRepo) UpdateTask(_ context.Context, task *models.Task, _ string) error {
+	r.updated = append(r.updated, *task)
+
+	return nil
+}
+
+func TestTaskDone(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	day := func(offset int) string {
+		return now.AddDate(0, 0, offset).Format("20060102")
+	}
+
+	tests := []struct {
+		name string
+		task models.Task
+		want string
+	}{
+		{
+			name: "not snoozed",
+			task: models.Task{Date: day(-1), Repeat: "d 7"},
+			want: day(6),
+		},
+		{
+			// Задача от вчера отложена на 10 дней вперёд: следующая дата правила
+			// после отложенной, а не ближайшая после сегодня.
+			name: "done before the snoozed date",
+			task: models.Task{Date: day(10), SnoozedFrom: day(-1), Repeat: "d 7"},
+			want: day(13),
+		},
+		{
+			name: "done after the snoozed date",
+			task: models.Task{Date: day(-3), SnoozedFrom: day(-10), Repeat: "d 7"},
+			want: day(4),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.task.ID = "0192f0c4-7d3a-7b6e-9c1d-2f4a5b6c7d8e"
+			tt.task.Title = "Gym"
+			repo := &taskRepo{tasks: []models.Task{tt.task}}
+
+			s, err := usecases.NewTodoTask(&usecases.TodoTaskDependencies{
+				TodoTaskRepo: repo,
+				Outbox:       nopOutbox{},
+				Metrics:      nopMetrics{},
+			})
+			if err != nil {
+				t.Fatalf("NewTodoTask: %v", err)
+			}
+
+			if err = s.TaskDone(context.Background(), &models.SelectConfig{ID: tt.task.ID}); err != nil {
+				t.Fatalf("TaskDone: %v", err)
+			}
+
+			if len(repo.updated) != 1 {
+				t.Fatalf("UpdateTask called %d times, want 1", len(repo.updated))
+			}
+
+			if got := repo.updated[0].Date; got != tt.want {
+				t.Errorf("TaskDone moved %s (snoozed from %q) to %s, want %s",
+					tt.task.Date, tt.task.SnoozedFrom, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskDoneUnknownID(t *testing.T) {
+	t.Parallel()
+
+	repo := &taskRepo{}
+
+	s, err := usecases.NewTodoTask(&usecases.TodoTaskDependencies{
+		TodoTaskRepo: repo,
+		Outbox:       nopOutbox{},
+		Metrics:      nopMetrics{},
+	})
+	if err != nil {
+		t.Fatalf("NewTodoTask: %v", err)
+	}
+
+	err = s.TaskDone(context.Background(), &models.SelectConfig{ID: "0192f0c4-7d3a-7b6e-9c1d-2f4a5b6c7d8e"})
+	if !errors.Is(err, errorspkg.ErrNotFound) {
+		t.Fatalf("TaskDone() error = %v, want %v", err, errorspkg.ErrNotFound)
+	}
+
+	if len(repo.updated) != 0 {
+		t.Errorf("UpdateTask called for an unknown task: %+v", repo.updated)
+	}
+}
@@ -0,0 +1,173 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+	"github.com/sater-151/todo-list/internal/utils/selectconfig"
+)
+
+const (
+	defaultViewLimit = 50
+	maxViewLimit     = 500
+)
+
+type (
+	IViewRepo interface {
+		InsertView(ctx context.Context, view *models.View) (string, error)
+		UpdateView(ctx context.Context, view *models.View) (bool, error)
+		DeleteView(ctx context.Context, uuid string) error
+		SelectViews(ctx context.Context, uuid string) ([]models.View, error)
+	}
+)
+
+type (
+	ViewDependencies struct {
+		ViewRepo     IViewRepo     `validate:"required"`
+		TodoTaskRepo ITodoTaskRepo `validate:"required"`
+	}
+
+	View struct {
+		viewRepo     IViewRepo
+		todoTaskRepo ITodoTaskRepo
+	}
+)
+
+func NewView(d *ViewDependencies) (*View, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("usecases.NewView", d, err)
+	}
+
+	return &View{
+		viewRepo:     d.ViewRepo,
+		todoTaskRepo: d.TodoTaskRepo,
+	}, nil
+}
+
+func (s *View) AddView(ctx context.Context, view *models.View) (string, error) {
+	if err := checkView(view); err != nil {
+		logctx.Warn(ctx, err.Error())
+
+		return "", errorspkg.ErrBadRequest
+	}
+
+	id, err := s.viewRepo.InsertView(ctx, view)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return "", errorspkg.ErrInternalError
+	}
+
+	return id, nil
+}
+
+func (s *View) UpdateView(ctx context.Context, view *models.View) error {
+	if err := checkView(view); err != nil {
+		logctx.Warn(ctx, err.Error())
+
+		return errorspkg.ErrBadRequest
+	}
+
+	found, err := s.viewRepo.UpdateView(ctx, view)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return errorspkg.ErrInternalError
+	}
+
+	if !found {
+		return errorspkg.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *View) DeleteView(ctx context.Context, uuid string) error {
+	if err := s.viewRepo.DeleteView(ctx, uuid); err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return errorspkg.ErrInternalError
+	}
+
+	return nil
+}
+
+func (s *View) ListViews(ctx context.Context) ([]models.View, error) {
+	views, err := s.viewRepo.SelectViews(ctx, "")
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	return views, nil
+}
+
+// ViewTasks выполняет сохранённый фильтр представления и возвращает limit задач,
+// начиная с offset; относительные периоды считаются от текущей даты.
+func (s *View) ViewTasks(ctx context.Context, uuid string, limit, offset int) (*models.ViewTasks, error) {
+	if offset < 0 {
+		logctx.Warn(ctx, "negative view offset", slog.Int("offset", offset))
+
+		return nil, errorspkg.ErrBadRequest
+	}
+
+	if limit <= 0 {
+		limit = defaultViewLimit
+	}
+
+	limit = min(limit, maxViewLimit)
+
+	views, err := s.viewRepo.SelectViews(ctx, uuid)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	if len(views) == 0 {
+		return nil, errorspkg.ErrNotFound
+	}
+
+	selectConfig, err := selectconfig.FromFilter(&views[0].Filter, time.Now())
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	// Лишняя задача показывает, что за страницей есть продолжение.
+	selectConfig.Limit = strconv.Itoa(limit + 1)
+	selectConfig.Offset = strconv.Itoa(offset)
+
+	tasks, err := s.todoTaskRepo.Select(ctx, selectConfig)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	page := &models.ViewTasks{Tasks: tasks}
+	if len(tasks) > limit {
+		page.Tasks = tasks[:limit]
+		page.NextOffset = offset + limit
+	}
+
+	return page, nil
+}
+
+func checkView(view *models.View) error {
+	if err := validate.Struct(view); err != nil {
+		return err
+	}
+
+	_, err := selectconfig.FromFilter(&view.Filter, time.Now())
+
+	return err
+}
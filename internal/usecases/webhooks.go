@@ -0,0 +1,344 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/logctx"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+const (
+	webhookSecretBytes     = 32
+	webhookBatchSize       = 50
+	webhookBackoffBase     = 30 * time.Second
+	webhookBackoffMax      = 6 * time.Hour
+	webhookResponseLimit   = 64 << 10
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 500
+
+	HeaderWebhookEvent     = "X-Todo-Event"
+	HeaderWebhookDelivery  = "X-Todo-Delivery"
+	HeaderWebhookSignature = "X-Todo-Signature"
+)
+
+type (
+	IWebhookRepo interface {
+		InsertWebhook(ctx context.Context, webhook *models.Webhook) (string, error)
+		UpdateWebhook(ctx context.Context, webhook *models.Webhook) (bool, error)
+		DeleteWebhook(ctx context.Context, uuid string) error
+		SelectWebhooks(ctx context.Context, uuid string) ([]models.Webhook, error)
+		EnqueueDeliveries(ctx context.Context, eventID, eventType string, payload []byte) (int, error)
+		ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+		RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, disableAfter int) (bool, error)
+		SelectDeliveries(ctx context.Context, webhookUUID string, limit int) ([]models.WebhookDelivery, error)
+	}
+
+	IHTTPDoer interface {
+		Do(req *http.Request) (*http.Response, error)
+	}
+)
+
+type (
+	WebhookDependencies struct {
+		WebhookRepo  IWebhookRepo  `validate:"required"`
+		Client       IHTTPDoer     `validate:"required"`
+		Timeout      time.Duration `validate:"gt=0"`
+		MaxAttempts  int           `validate:"gt=0"`
+		DisableAfter int           `validate:"gt=0"`
+	}
+
+	// WebhookSettings — настройки доставки, которые можно менять без перезапуска.
+	WebhookSettings struct {
+		Timeout      time.Duration `validate:"gt=0"`
+		MaxAttempts  int           `validate:"gt=0"`
+		DisableAfter int           `validate:"gt=0"`
+	}
+
+	Webhook struct {
+		webhookRepo IWebhookRepo
+		client      IHTTPDoer
+		settings    atomic.Pointer[WebhookSettings]
+	}
+)
+
+func NewWebhook(d *WebhookDependencies) (*Webhook, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, errorspkg.NewValidationError("usecases.NewWebhook", d, err)
+	}
+
+	s := &Webhook{
+		webhookRepo: d.WebhookRepo,
+		client:      d.Client,
+	}
+	s.settings.Store(&WebhookSettings{
+		Timeout:      d.Timeout,
+		MaxAttempts:  d.MaxAttempts,
+		DisableAfter: d.DisableAfter,
+	})
+
+	return s, nil
+}
+
+// SetSettings заменяет настройки доставки. Уже взятая пачка доставок
+// дорабатывает со старыми настройками.
+func (s *Webhook) SetSettings(settings *WebhookSettings) error {
+	if err := validate.Struct(settings); err != nil {
+		return errorspkg.NewValidationError("usecases.Webhook.SetSettings", settings, err)
+	}
+
+	s.settings.Store(settings)
+
+	return nil
+}
+
+// AddWebhook регистрирует вебхук. Если секрет не задан, он генерируется;
+// секрет возвращается только в ответе на создание.
+func (s *Webhook) AddWebhook(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	if err := validate.Struct(webhook); err != nil {
+		logctx.Warn(ctx, err.Error())
+
+		return nil, errorspkg.ErrBadRequest
+	}
+
+	if webhook.Secret == "" {
+		secret := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(secret); err != nil {
+			logctx.Error(ctx, err.Error())
+
+			return nil, errorspkg.ErrInternalError
+		}
+
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+
+	id, err := s.webhookRepo.InsertWebhook(ctx, webhook)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	webhook.ID = id
+	webhook.Enabled = true
+	webhook.Failures = 0
+	webhook.DisabledReason = ""
+
+	return webhook, nil
+}
+
+func (s *Webhook) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	if err := validate.Struct(webhook); err != nil {
+		logctx.Warn(ctx, err.Error())
+
+		return errorspkg.ErrBadRequest
+	}
+
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+
+	found, err := s.webhookRepo.UpdateWebhook(ctx, webhook)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return errorspkg.ErrInternalError
+	}
+
+	if !found {
+		return errorspkg.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *Webhook) DeleteWebhook(ctx context.Context, uuid string) error {
+	if err := s.webhookRepo.DeleteWebhook(ctx, uuid); err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return errorspkg.ErrInternalError
+	}
+
+	return nil
+}
+
+func (s *Webhook) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	webhooks, err := s.webhookRepo.SelectWebhooks(ctx, "")
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// ListDeliveries возвращает журнал доставок вебхука, новые записи первыми.
+func (s *Webhook) ListDeliveries(ctx context.Context, uuid string, limit int) ([]models.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+
+	limit = min(limit, maxDeliveriesLimit)
+
+	webhooks, err := s.webhookRepo.SelectWebhooks(ctx, uuid)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	if len(webhooks) == 0 {
+		return nil, errorspkg.ErrNotFound
+	}
+
+	deliveries, err := s.webhookRepo.SelectDeliveries(ctx, uuid, limit)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return nil, errorspkg.ErrInternalError
+	}
+
+	return deliveries, nil
+}
+
+// HandleEvent ставит событие задачи в очередь доставки всем подписанным вебхукам.
+// Повторная передача того же события из outbox не создаёт дублей доставок.
+func (s *Webhook) HandleEvent(ctx context.Context, event models.TaskEvent) error {
+	if event.Type == models.TaskEventReset {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("webhook payload: %w", err)
+	}
+
+	if _, err = s.webhookRepo.EnqueueDeliveries(ctx, event.ID, event.Type, payload); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeliverDue отправляет доставки, время которых пришло. Возвращает число
+// обработанных доставок.
+func (s *Webhook) DeliverDue(ctx context.Context) (int, error) {
+	settings := s.settings.Load()
+
+	// Аренда покрывает отправку всей пачки, даже если каждый запрос упрётся в таймаут.
+	deliveries, err := s.webhookRepo.ClaimDeliveries(ctx, webhookBatchSize, webhookBatchSize*settings.Timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			return i, ctx.Err()
+		}
+
+		s.deliver(ctx, &deliveries[i], settings)
+	}
+
+	return len(deliveries), nil
+}
+
+func (s *Webhook) deliver(ctx context.Context, d *models.WebhookDelivery, settings *WebhookSettings) {
+	code, err := s.send(ctx, d, settings.Timeout)
+
+	now := time.Now()
+	d.Attempts++
+	d.ResponseCode = code
+	d.LastError = ""
+
+	switch {
+	case err == nil:
+		d.Status = models.DeliveryDelivered
+		d.DeliveredAt = &now
+	case d.Attempts >= settings.MaxAttempts:
+		d.Status = models.DeliveryFailed
+		d.LastError = err.Error()
+	default:
+		next := now.Add(backoff(d.Attempts, webhookBackoffBase, webhookBackoffMax))
+		d.Status = models.DeliveryPending
+		d.NextAttemptAt = &next
+		d.LastError = err.Error()
+	}
+
+	disabled, err := s.webhookRepo.RecordAttempt(ctx, d, settings.DisableAfter)
+	if err != nil {
+		logctx.Error(ctx, err.Error())
+
+		return
+	}
+
+	if disabled {
+		logctx.Warn(ctx, "webhook disabled after repeated failures",
+			slog.String("webhook", d.WebhookID), slog.String("error", d.LastError))
+	}
+}
+
+func (s *Webhook) send(ctx context.Context, d *models.WebhookDelivery, timeout time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("building request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-list-webhooks")
+	req.Header.Set(HeaderWebhookEvent, d.EventType)
+	req.Header.Set(HeaderWebhookDelivery, d.ID)
+	req.Header.Set(HeaderWebhookSignature, Sign(d.Secret, time.Now(), d.Payload))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("sending request: %w", err)
+	}
+	defer res.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, webhookResponseLimit))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
+
+// Sign возвращает подпись тела запроса в виде "t=<unix>,v1=<hex>", где v1 —
+// HMAC-SHA256 секрета от строки "<unix>.<тело>". Метка времени в подписи позволяет
+// получателю отбрасывать повторно отправленные старые запросы.
+func Sign(secret string, at time.Time, payload []byte) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(payload)
+
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,225 @@
+package backup
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sater-151/todo-list/internal/models"
+)
+
+const (
+	columnID      = "id"
+	columnDate    = "date"
+	columnTitle   = "title"
+	columnComment = "comment"
+	columnRepeat  = "repeat"
+	columnSnoozed = "snoozed_from"
+)
+
+var columns = []string{columnID, columnDate, columnTitle, columnComment, columnRepeat, columnSnoozed}
+
+// record — задача в резервной копии. Отдельный тип нужен, чтобы в копию
+// не попадали вычисляемые поля ответа API.
+type record struct {
+	ID      string `json:"id"`
+	Date    string `json:"date"`
+	Title   string `json:"title"`
+	Comment string `json:"comment"`
+	Repeat  string `json:"repeat"`
+	Snoozed string `json:"snoozed_from,omitempty"`
+}
+
+func newRecord(task *models.Task) record {
+	return record{
+		ID:      task.ID,
+		Date:    task.Date,
+		Title:   task.Title,
+		Comment: task.Comment,
+		Repeat:  task.Repeat,
+		Snoozed: task.SnoozedFrom,
+	}
+}
+
+func (r *record) task() models.Task {
+	return models.Task{
+		ID:          r.ID,
+		Date:        r.Date,
+		Title:       r.Title,
+		Comment:     r.Comment,
+		Repeat:      r.Repeat,
+		SnoozedFrom: r.Snoozed,
+	}
+}
+
+// JSONEncoder пишет задачи JSON-массивом по мере поступления.
+type JSONEncoder struct {
+	w     *bufio.Writer
+	count int
+}
+
+func NewJSONEncoder(w io.Writer) *JSONEncoder {
+	return &JSONEncoder{w: bufio.NewWriter(w)}
+}
+
+func (e *JSONEncoder) Encode(task *models.Task) error {
+	data, err := json.Marshal(newRecord(task))
+	if err != nil {
+		return fmt.Errorf("encoding task [%s]: %w", task.ID, err)
+	}
+
+	sep := ",\n"
+	if e.count == 0 {
+		sep = "[\n"
+	}
+
+	e.count++
+
+	if _, err := e.w.WriteString(sep); err != nil {
+		return fmt.Errorf("writing json: %w", err)
+	}
+
+	if _, err := e.w.Write(data); err != nil {
+		return fmt.Errorf("writing json: %w", err)
+	}
+
+	return nil
+}
+
+// Close закрывает массив. Без задач пишется пустой массив.
+func (e *JSONEncoder) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+
+	if _, err := e.w.WriteString(end); err != nil {
+		return fmt.Errorf("writing json: %w", err)
+	}
+
+	return e.w.Flush()
+}
+
+// CSVEncoder пишет задачи в CSV с заголовком id,date,title,comment,repeat,snoozed_from.
+type CSVEncoder struct {
+	w      *csv.Writer
+	header bool
+}
+
+func NewCSVEncoder(w io.Writer) *CSVEncoder {
+	return &CSVEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *CSVEncoder) Encode(task *models.Task) error {
+	if !e.header {
+		if err := e.w.Write(columns); err != nil {
+			return fmt.Errorf("writing csv: %w", err)
+		}
+
+		e.header = true
+	}
+
+	row := []string{task.ID, task.Date, task.Title, task.Comment, task.Repeat, task.SnoozedFrom}
+	if err := e.w.Write(row); err != nil {
+		return fmt.Errorf("writing csv: %w", err)
+	}
+
+	return nil
+}
+
+func (e *CSVEncoder) Close() error {
+	if !e.header {
+		if err := e.w.Write(columns); err != nil {
+			return fmt.Errorf("writing csv: %w", err)
+		}
+	}
+
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+// DecodeJSON читает JSON-массив задач, разбирая элементы по одному.
+func DecodeJSON(r io.Reader) ([]models.Task, error) {
+	dec := json.NewDecoder(r)
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, fmt.Errorf("json backup must be an array of tasks")
+	}
+
+	var tasks []models.Task
+	for dec.More() {
+		var rec record
+		if err := dec.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("decoding task #%d: %w", len(tasks)+1, err)
+		}
+
+		tasks = append(tasks, rec.task())
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("decoding json backup: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// DecodeCSV читает задачи из CSV. Столбцы определяются по заголовку, обязателен
+// только title. Неизвестные столбцы игнорируются.
+func DecodeCSV(r io.Reader) ([]models.Task, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("csv backup is empty")
+		}
+
+		return nil, fmt.Errorf("reading csv header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	if _, ok := index[columnTitle]; !ok {
+		return nil, fmt.Errorf("csv header has no %q column", columnTitle)
+	}
+
+	field := func(row []string, name string) string {
+		if i, ok := index[name]; ok {
+			return row[i]
+		}
+
+		return ""
+	}
+
+	var tasks []models.Task
+
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("reading csv: %w", err)
+		}
+
+		tasks = append(tasks, models.Task{
+			ID:          field(row, columnID),
+			Date:        field(row, columnDate),
+			Title:       field(row, columnTitle),
+			Comment:     field(row, columnComment),
+			Repeat:      field(row, columnRepeat),
+			SnoozedFrom: field(row, columnSnoozed),
+		})
+	}
+
+	return tasks, nil
+}
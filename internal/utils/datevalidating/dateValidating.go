@@ -1,298 +1,99 @@
-// nolint: gocyclo
 package datevalidating
 
 import (
 	"fmt"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/sater-151/todo-list/internal/models"
-	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
 )
 
 func CheckCorrectRepeat(repeat string) error {
-	const method = "CheckCorrectRepeat"
+	_, err := ParseRepeat(repeat)
 
-	switch {
-	case repeat == "":
-		return fmt.Errorf("repeat is empty")
-
-	case repeat == "y":
-		return nil
-
-	case string(repeat[0]) == "d" && len(repeat) > 1:
-		repeatMas := strings.Split(repeat, " ")
-		d, err := strconv.Atoi(repeatMas[1])
-		if err != nil {
-			return errorspkg.NewStrconvError(method, repeatMas[1], err)
-		}
-
-		if len(repeatMas) == 2 && d <= 400 {
-			return nil
-		}
-
-	case string(repeat[0]) == "w" && len(repeat) > 1:
-		repeatMas := strings.Split(repeat, " ")
-
-		weekday := strings.Split(repeatMas[1], ",")
-		if len(weekday) > 7 || len(weekday) == 0 {
-			return fmt.Errorf("incorrect repeat")
-		}
-
-		for i := 0; i < len(weekday); i++ {
-			buf, err := strconv.Atoi(weekday[i])
-			if err != nil {
-				return errorspkg.NewStrconvError(method, weekday[i], err)
-			}
-
-			if buf > 7 || buf < 1 {
-				return fmt.Errorf("incorrect repeat")
-			}
-		}
-
-		return nil
-
-	case string(repeat[0]) == "m" && len(repeat) > 1:
-		repeatMas := strings.Split(repeat, " ")
-		if len(repeatMas) == 2 || len(repeatMas) == 3 {
-			days := strings.Split(repeatMas[1], ",")
-
-			for _, i := range days {
-				buf, err := strconv.Atoi(i)
-				if err != nil {
-					return errorspkg.NewStrconvError(method, i, err)
-				}
-				if buf < (-2) || buf == 0 || buf > 31 {
-					return fmt.Errorf("incorrect repeat")
-				}
-			}
-
-			if len(repeatMas) == 3 {
-				months := strings.Split(repeatMas[2], ",")
-				for _, i := range months {
-					buf, err := strconv.Atoi(i)
-					if err != nil {
-						return errorspkg.NewStrconvError(method, i, err)
-					}
-
-					if buf < 1 || buf > 12 {
-						return fmt.Errorf("incorrect repeat")
-					}
-				}
-
-			}
-		} else {
-			return fmt.Errorf("incorrect repeat")
-		}
-
-		return nil
-	}
-
-	return fmt.Errorf("incorrect repeat")
+	return err
 }
 
 func NextDate(now time.Time, date, repeat string) (string, error) {
-	const method = "NextDate"
-
-	err := CheckCorrectRepeat(repeat)
+	rule, err := ParseRepeat(repeat)
 	if err != nil {
 		return "", err
 	}
 
-	dateParse, err := time.Parse("20060102", date)
+	dateParse, err := time.Parse(dateLayout, date)
 	if err != nil {
-		return "", fmt.Errorf(fmt.Sprintf("failed to parse task.Date to time: error=%s", err.Error()))
+		return "", fmt.Errorf("failed to parse task.Date to time: error=%w", err)
 	}
 
-	repeatParse := strings.Split(repeat, " ")
-	switch string(repeat[0]) {
-	case "y":
-		for {
-			dateParse = dateParse.AddDate(1, 0, 0)
-			if now.Compare(dateParse) == -1 {
-				return dateParse.Format("20060102"), nil
-			}
-		}
-
-	case "d":
-		days, err := strconv.Atoi(repeatParse[1])
-		if err != nil {
-			return "", errorspkg.NewStrconvError(method, repeatParse[1], err)
-		}
-
-		for {
-			dateParse = dateParse.AddDate(0, 0, days)
-			if now.Compare(dateParse) == -1 {
-				return dateParse.Format("20060102"), nil
-			}
-
-		}
-
-	case "w":
-		weekday := strings.Split(repeatParse[1], ",")
-		var weekdayParse []int
-		for i := 0; i < len(weekday); i++ {
-			buf, err := strconv.Atoi(weekday[i])
-			if err != nil {
-				return "", errorspkg.NewStrconvError(method, weekday[i], err)
-			}
-			if buf == 7 {
-				buf = 0
-			}
-
-			weekdayParse = append(weekdayParse, buf)
-		}
-		for {
-			dateParse = dateParse.AddDate(0, 0, 1)
-			weekdayNow := dateParse.Weekday()
-			for _, i := range weekdayParse {
-				if i == int(weekdayNow) && now.Compare(dateParse) == -1 {
-					return dateParse.Format("20060102"), nil
-				}
-			}
-		}
-
-	case "m":
-		if len(repeatParse) == 2 {
-			numbers := strings.Split(repeatParse[1], ",")
-			for {
-				dateParse = dateParse.AddDate(0, 0, 1)
-				for _, i := range numbers {
-					if i == "-1" {
-						buf := dateParse.AddDate(0, 0, 1)
+	next, err := rule.Next(now, dateParse)
+	if err != nil {
+		return "", fmt.Errorf("next date for repeat [%s] from [%s]: %w", repeat, date, err)
+	}
 
-						if dateParse.Format("01") != buf.Format("01") && now.Compare(dateParse) == -1 {
-							return dateParse.Format("20060102"), nil
-						}
+	return next.Format(dateLayout), nil
+}
 
-						continue
-					}
-					if i == "-2" {
-						buf := dateParse.AddDate(0, 0, 2)
-						if dateParse.Format("01") != buf.Format("01") && now.Compare(dateParse) == -1 {
-							return dateParse.Format("20060102"), nil
-						}
+// ResumeDate возвращает дату, с которой задача, отложенная на date, продолжает
+// расписание от snoozedFrom: первую дату правила позже обеих.
+func ResumeDate(date, snoozedFrom, repeat string) (string, error) {
+	after, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse task.Date to time: error=%w", err)
+	}
 
-						continue
-					}
-					a, err := strconv.Atoi(i)
-					if err != nil {
-						return "", errorspkg.NewStrconvError(method, i, err)
-					}
+	return NextDate(after, snoozedFrom, repeat)
+}
 
-					b, err := strconv.Atoi(dateParse.Format("2"))
-					if err != nil {
-						return "", errorspkg.NewStrconvError(method, dateParse.Format("2"), err)
-					}
+// CheckStoredTask проверяет задачу из резервной копии. В отличие от CheckTask
+// прошедшие даты не переносятся: задача восстанавливается такой, какой была.
+func CheckStoredTask(task *models.Task) (*models.Task, error) {
+	if task.Title == "" {
+		return task, fmt.Errorf("title is empty")
+	}
 
-					if a == b && now.Compare(dateParse) == -1 {
-						return dateParse.Format("20060102"), nil
-					}
-				}
-			}
+	if task.Repeat != "" {
+		if err := CheckCorrectRepeat(task.Repeat); err != nil {
+			return task, err
 		}
-		if len(repeatParse) == 3 {
-			numbers := strings.Split(repeatParse[1], ",")
-			months := strings.Split(repeatParse[2], ",")
-			for {
-				dateParse = dateParse.AddDate(0, 0, 1)
-				for _, i := range numbers {
-					if i == "-1" {
-						buf := dateParse.AddDate(0, 0, 1)
-
-						if dateParse.Format("01") != buf.Format("01") {
-							for _, j := range months {
-								c, err := strconv.Atoi(j)
-								if err != nil {
-									return "", errorspkg.NewStrconvError(method, j, err)
-								}
-
-								d, err := strconv.Atoi(dateParse.Format("1"))
-								if err != nil {
-									return "", errorspkg.NewStrconvError(method, dateParse.Format("1"), err)
-								}
-
-								if d == c && now.Compare(dateParse) == -1 {
-									return dateParse.Format("20060102"), nil
-								}
-							}
-						}
-
-						continue
-					}
-					if i == "-2" {
-						buf := dateParse.AddDate(0, 0, 2)
-						if dateParse.Format("01") != buf.Format("01") {
-							for _, j := range months {
-								c, err := strconv.Atoi(j)
-								if err != nil {
-									return "", errorspkg.NewStrconvError(method, j, err)
-								}
-
-								d, err := strconv.Atoi(dateParse.Format("1"))
-								if err != nil {
-									return "", errorspkg.NewStrconvError(method, dateParse.Format("1"), err)
-								}
-								if d == c && now.Compare(dateParse) == -1 {
-									return dateParse.Format("20060102"), nil
-								}
-							}
-						}
-
-						continue
-					}
-					a, err := strconv.Atoi(i)
-					if err != nil {
-						return "", errorspkg.NewStrconvError(method, i, err)
-					}
-
-					b, err := strconv.Atoi(dateParse.Format("2"))
-					if err != nil {
-						return "", errorspkg.NewStrconvError(method, dateParse.Format("2"), err)
-					}
+	}
 
-					if a == b {
-						for _, j := range months {
-							c, err := strconv.Atoi(j)
-							if err != nil {
-								return "", errorspkg.NewStrconvError(method, j, err)
-							}
+	if task.Date == "" {
+		task.Date = time.Now().Format(dateLayout)
+	}
 
-							d, err := strconv.Atoi(dateParse.Format("1"))
-							if err != nil {
-								return "", errorspkg.NewStrconvError(method, dateParse.Format("1"), err)
-							}
+	if _, err := time.Parse(dateLayout, task.Date); err != nil {
+		return task, fmt.Errorf("failed to parse task.Date to time: error=%w", err)
+	}
 
-							if d == c && now.Compare(dateParse) == -1 {
-								return dateParse.Format("20060102"), nil
-							}
-						}
-					}
-				}
-			}
+	if task.SnoozedFrom != "" {
+		if _, err := time.Parse(dateLayout, task.SnoozedFrom); err != nil {
+			return task, fmt.Errorf("failed to parse task.SnoozedFrom to time: error=%w", err)
 		}
 	}
 
-	return "", fmt.Errorf("incorrect date")
+	return task, nil
 }
 
 func CheckTask(task *models.Task) (*models.Task, error) {
 	if task.Title == "" {
 		return task, fmt.Errorf("title is empty")
 	}
+	if task.Repeat != "" {
+		if err := CheckCorrectRepeat(task.Repeat); err != nil {
+			return task, err
+		}
+	}
 	if task.Date == "" {
-		task.Date = time.Now().Format("20060102")
+		task.Date = time.Now().Format(dateLayout)
 	} else {
-		t, err := time.Parse("20060102", task.Date)
+		t, err := time.Parse(dateLayout, task.Date)
 		if err != nil {
-			return task, fmt.Errorf(fmt.Sprintf("failed to parse task.Date to time: error=%v", err))
+			return task, fmt.Errorf("failed to parse task.Date to time: error=%w", err)
 		}
 
-		now := time.Now().Format("20060102")
+		now := time.Now().Format(dateLayout)
 		if t.Compare(time.Now()) == -1 && now != task.Date {
 			if task.Repeat == "" {
-				task.Date = time.Now().Format("20060102")
+				task.Date = time.Now().Format(dateLayout)
 			} else {
 				task.Date, err = NextDate(time.Now(), task.Date, task.Repeat)
 				if err != nil {
@@ -304,3 +105,13 @@ func CheckTask(task *models.Task) (*models.Task, error) {
 
 	return task, nil
 }
+
+// ParseDotDate переводит дату вида dd.mm.yyyy в формат задач yyyymmdd.
+func ParseDotDate(s string) (string, bool) {
+	t, err := time.Parse("02.01.2006", s)
+	if err != nil {
+		return "", false
+	}
+
+	return t.Format(dateLayout), true
+}
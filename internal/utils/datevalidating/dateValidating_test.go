@@ -0,0 +1,182 @@
+package datevalidating
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+)
+
+func TestNextDate(t *testing.T) {
+	// Пятница, последняя в январе 2024 года.
+	now := time.Date(2024, time.January, 26, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		date    string
+		repeat  string
+		want    string
+		wantErr bool
+	}{
+		{name: "yearly", date: "20200115", repeat: "y", want: "20250115"},
+		{name: "yearly from leap day", date: "20240229", repeat: "y", want: "20250301"},
+		{name: "daily", date: "20240126", repeat: "d 1", want: "20240127"},
+		{name: "daily catches up with now", date: "20240101", repeat: "d 7", want: "20240129"},
+		{name: "daily in future", date: "20240301", repeat: "d 400", want: "20250405"},
+		{name: "weekly", date: "20240126", repeat: "w 1,5", want: "20240129"},
+		{name: "weekly sunday", date: "20240126", repeat: "w 7", want: "20240128"},
+		{name: "weekly every second week", date: "20240101", repeat: "w 3 / 2", want: "20240131"},
+		{
+			name:   "weekly interval counts from date",
+			now:    time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC),
+			date:   "20240101",
+			repeat: "w 7 / 3",
+			want:   "20240107",
+		},
+		{name: "monthly day", date: "20240126", repeat: "m 1,15", want: "20240201"},
+		{name: "monthly 31st skips short months", date: "20240131", repeat: "m 31", want: "20240331"},
+		{name: "monthly last day", date: "20240126", repeat: "m -1", want: "20240131"},
+		{name: "monthly day before last in leap february", date: "20240131", repeat: "m -2", want: "20240228"},
+		{name: "monthly last day of leap february", date: "20240126", repeat: "m -1 2", want: "20240229"},
+		{
+			name:   "monthly last day of february",
+			now:    time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC),
+			date:   "20250101",
+			repeat: "m -1 2",
+			want:   "20250228",
+		},
+		{name: "monthly 29th of february waits for leap year", date: "20240301", repeat: "m 29 2", want: "20280229"},
+		{name: "monthly with months", date: "20240126", repeat: "m 1,15 3,6", want: "20240301"},
+		{name: "second tuesday", date: "20240126", repeat: "m 2tue", want: "20240213"},
+		{name: "last friday", date: "20240126", repeat: "m -1fri", want: "20240223"},
+		{name: "fifth friday", date: "20240126", repeat: "m 5fri", want: "20240329"},
+		{name: "weekday and day mixed", date: "20240126", repeat: "m 2tue,-1", want: "20240131"},
+		{name: "empty repeat", date: "20240126", repeat: "", wantErr: true},
+		{name: "invalid repeat", date: "20240126", repeat: "m 31 2", wantErr: true},
+		{name: "invalid date", date: "2024013", repeat: "d 1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ttNow := now
+			if !tt.now.IsZero() {
+				ttNow = tt.now
+			}
+
+			got, err := NextDate(ttNow, tt.date, tt.repeat)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NextDate(%s, %q, %q) error = %v, wantErr %v",
+					ttNow.Format(dateLayout), tt.date, tt.repeat, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("NextDate(%s, %q, %q) = %q, want %q",
+					ttNow.Format(dateLayout), tt.date, tt.repeat, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRepeat(t *testing.T) {
+	tests := []struct {
+		repeat string
+		want   *Rule
+	}{
+		{repeat: "y", want: &Rule{Kind: Yearly}},
+		{repeat: " d  3 ", want: &Rule{Kind: Daily, Interval: 3}},
+		{repeat: "w 1,7", want: &Rule{Kind: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Monday, time.Sunday}}},
+		{repeat: "w 3 / 2", want: &Rule{Kind: Weekly, Interval: 2, Weekdays: []time.Weekday{time.Wednesday}}},
+		{
+			repeat: "m 2tue,-1FRI,-2,31 1,12",
+			want: &Rule{
+				Kind: Monthly,
+				MonthDays: []MonthDay{
+					{Nth: 2, Weekday: time.Tuesday},
+					{Nth: -1, Weekday: time.Friday},
+					{Day: -2},
+					{Day: 31},
+				},
+				Months: []time.Month{time.January, time.December},
+			},
+		},
+		{repeat: "m 30 2,4", want: &Rule{Kind: Monthly, MonthDays: []MonthDay{{Day: 30}}, Months: []time.Month{2, 4}}},
+		{repeat: "m 29 2", want: &Rule{Kind: Monthly, MonthDays: []MonthDay{{Day: 29}}, Months: []time.Month{2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repeat, func(t *testing.T) {
+			got, err := ParseRepeat(tt.repeat)
+			if err != nil {
+				t.Fatalf("ParseRepeat(%q) error: %v", tt.repeat, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRepeat(%q) =\n %#v\nwant\n %#v", tt.repeat, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRepeatErrorPosition(t *testing.T) {
+	tests := []struct {
+		repeat string
+		pos    int
+	}{
+		{repeat: "", pos: 1},
+		{repeat: "   ", pos: 1},
+		{repeat: "x", pos: 1},
+		{repeat: "dd 1", pos: 1},
+		{repeat: "d", pos: 2},
+		{repeat: "d 0", pos: 3},
+		{repeat: "d 401", pos: 3},
+		{repeat: "d two", pos: 3},
+		{repeat: "d 1 2", pos: 5},
+		{repeat: "y 1", pos: 3},
+		{repeat: "w", pos: 2},
+		{repeat: "w 0", pos: 3},
+		{repeat: "w 1,8", pos: 5},
+		{repeat: "w 1,,2", pos: 5},
+		{repeat: "w 1 x", pos: 5},
+		{repeat: "w 1 /", pos: 6},
+		{repeat: "w 1 / 0", pos: 7},
+		{repeat: "w 1 / 53", pos: 7},
+		{repeat: "w 1 / 2 3", pos: 9},
+		{repeat: "m", pos: 2},
+		{repeat: "m 0", pos: 3},
+		{repeat: "m -3", pos: 3},
+		{repeat: "m 32", pos: 3},
+		{repeat: "m 1,0", pos: 5},
+		{repeat: "m 6fri", pos: 3},
+		{repeat: "m 0fri", pos: 3},
+		{repeat: "m 2xyz", pos: 4},
+		{repeat: "m 1,-1xyz", pos: 7},
+		{repeat: "m 1 13", pos: 5},
+		{repeat: "m 1 1,", pos: 7},
+		{repeat: "m 31 2", pos: 3},
+		{repeat: "m 30,31 2", pos: 3},
+		{repeat: "m 31 4,6,9,11", pos: 3},
+		{repeat: "m 1 2 3", pos: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repeat, func(t *testing.T) {
+			_, err := ParseRepeat(tt.repeat)
+
+			var perr errorspkg.RepeatParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("ParseRepeat(%q) error = %v, want RepeatParseError", tt.repeat, err)
+			}
+
+			if perr.Position != tt.pos {
+				t.Errorf("ParseRepeat(%q) position = %d, want %d (%v)", tt.repeat, perr.Position, tt.pos, err)
+			}
+
+			if perr.Repeat != tt.repeat {
+				t.Errorf("ParseRepeat(%q) repeat = %q", tt.repeat, perr.Repeat)
+			}
+		})
+	}
+}
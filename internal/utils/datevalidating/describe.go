@@ -0,0 +1,273 @@
+package datevalidating
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Language string
+
+const (
+	LanguageRU Language = "ru"
+	LanguageEN Language = "en"
+
+	DefaultLanguage = LanguageRU
+)
+
+var (
+	monthsEN = [...]string{"", "January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"}
+	monthsRUGenitive = [...]string{"", "января", "февраля", "марта", "апреля", "мая", "июня",
+		"июля", "августа", "сентября", "октября", "ноября", "декабря"}
+	weekdaysRUDativePlural = [...]string{"воскресеньям", "понедельникам", "вторникам", "средам",
+		"четвергам", "пятницам", "субботам"}
+	weekdaysRUAccusative = [...]string{"воскресенье", "понедельник", "вторник", "среду",
+		"четверг", "пятницу", "субботу"}
+)
+
+// DescribeRepeat возвращает описание правила повторения на языке lang.
+// Для пустого или некорректного правила возвращается пустая строка.
+func DescribeRepeat(repeat string, lang Language) string {
+	if repeat == "" {
+		return ""
+	}
+
+	rule, err := ParseRepeat(repeat)
+	if err != nil {
+		return ""
+	}
+
+	return rule.Describe(lang)
+}
+
+func (r *Rule) Describe(lang Language) string {
+	if lang == LanguageEN {
+		return r.describeEN()
+	}
+
+	return r.describeRU()
+}
+
+func (r *Rule) describeEN() string {
+	switch r.Kind {
+	case Yearly:
+		return "every year"
+
+	case Daily:
+		if r.Interval == 1 {
+			return "every day"
+		}
+
+		return fmt.Sprintf("every %d days", r.Interval)
+
+	case Weekly:
+		days := make([]string, 0, len(r.Weekdays))
+		for _, wd := range r.Weekdays {
+			days = append(days, wd.String())
+		}
+
+		if r.Interval == 1 {
+			return "every " + joinList(days, "and")
+		}
+
+		return fmt.Sprintf("every %d weeks on %s", r.Interval, joinList(days, "and"))
+
+	case Monthly:
+		mixed := false
+		for _, d := range r.MonthDays {
+			mixed = mixed || d.Nth != 0
+		}
+
+		items := make([]string, 0, len(r.MonthDays))
+		for i, d := range r.MonthDays {
+			item := d.describeEN()
+			if d.Day > 0 && (mixed || i == len(r.MonthDays)-1) {
+				item += " day"
+			}
+
+			items = append(items, item)
+		}
+
+		return fmt.Sprintf("on the %s of %s", joinList(items, "and"), r.monthsEN())
+	}
+
+	return ""
+}
+
+func (d MonthDay) describeEN() string {
+	var n string
+
+	switch {
+	case d.Day > 0:
+		return ordinalEN(d.Day)
+	case d.Day == -1 || d.Nth == -1:
+		n = "last"
+	case d.Day == -2 || d.Nth == -2:
+		n = "second-to-last"
+	case d.Nth > 0:
+		n = ordinalEN(d.Nth)
+	default:
+		n = ordinalEN(-d.Nth) + "-to-last"
+	}
+
+	if d.Nth == 0 {
+		return n + " day"
+	}
+
+	return n + " " + d.Weekday.String()
+}
+
+func (r *Rule) monthsEN() string {
+	if len(r.Months) == 0 {
+		return "every month"
+	}
+
+	names := make([]string, 0, len(r.Months))
+	for _, m := range r.Months {
+		names = append(names, monthsEN[m])
+	}
+
+	return joinList(names, "and")
+}
+
+func (r *Rule) describeRU() string {
+	switch r.Kind {
+	case Yearly:
+		return "каждый год"
+
+	case Daily:
+		if r.Interval == 1 {
+			return "каждый день"
+		}
+
+		return fmt.Sprintf("%s %d %s", pluralRU(r.Interval, "каждый", "каждые", "каждые"),
+			r.Interval, pluralRU(r.Interval, "день", "дня", "дней"))
+
+	case Weekly:
+		days := make([]string, 0, len(r.Weekdays))
+		for _, wd := range r.Weekdays {
+			days = append(days, weekdaysRUDativePlural[wd])
+		}
+
+		if r.Interval == 1 {
+			return "по " + joinList(days, "и")
+		}
+
+		return fmt.Sprintf("раз в %d %s по %s", r.Interval,
+			pluralRU(r.Interval, "неделю", "недели", "недель"), joinList(days, "и"))
+
+	case Monthly:
+		var numbers, weekdays []string
+		for _, d := range r.MonthDays {
+			if d.Nth == 0 {
+				numbers = append(numbers, d.describeRU())
+			} else {
+				weekdays = append(weekdays, d.describeRU())
+			}
+		}
+
+		clauses := weekdays
+		if len(numbers) > 0 {
+			clauses = append([]string{joinList(numbers, "и") + " числа"}, weekdays...)
+		}
+
+		return joinList(clauses, "и") + " " + r.monthsRU()
+	}
+
+	return ""
+}
+
+func (d MonthDay) describeRU() string {
+	if d.Nth == 0 {
+		switch d.Day {
+		case -1:
+			return "последнего"
+		case -2:
+			return "предпоследнего"
+		default:
+			return fmt.Sprintf("%d-го", d.Day)
+		}
+	}
+
+	// Род существительного определяет окончание порядкового числительного:
+	// "в последний вторник", "в последнюю пятницу", "в последнее воскресенье".
+	var ending, last, penult string
+
+	switch d.Weekday {
+	case time.Wednesday, time.Friday, time.Saturday:
+		ending, last, penult = "ю", "последнюю", "предпоследнюю"
+	case time.Sunday:
+		ending, last, penult = "е", "последнее", "предпоследнее"
+	default:
+		ending, last, penult = "й", "последний", "предпоследний"
+	}
+
+	var n string
+
+	switch {
+	case d.Nth == -1:
+		n = last
+	case d.Nth == -2:
+		n = penult
+	case d.Nth > 0:
+		n = fmt.Sprintf("%d-%s", d.Nth, ending)
+	default:
+		n = fmt.Sprintf("%d-%s с конца", -d.Nth, ending)
+	}
+
+	prep := "в"
+	if d.Nth == 2 {
+		prep = "во"
+	}
+
+	return prep + " " + n + " " + weekdaysRUAccusative[d.Weekday]
+}
+
+func (r *Rule) monthsRU() string {
+	if len(r.Months) == 0 {
+		return "каждого месяца"
+	}
+
+	names := make([]string, 0, len(r.Months))
+	for _, m := range r.Months {
+		names = append(names, monthsRUGenitive[m])
+	}
+
+	return joinList(names, "и")
+}
+
+func joinList(items []string, conj string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+
+	return strings.Join(items[:len(items)-1], ", ") + " " + conj + " " + items[len(items)-1]
+}
+
+func ordinalEN(n int) string {
+	suffix := "th"
+
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+func pluralRU(n int, one, few, many string) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return one
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return few
+	default:
+		return many
+	}
+}
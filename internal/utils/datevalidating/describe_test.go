@@ -0,0 +1,56 @@
+package datevalidating_test
+
+import (
+	"testing"
+
+	"github.com/sater-151/todo-list/internal/utils/datevalidating"
+)
+
+func TestDescribeRepeat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		repeat string
+		en     string
+		ru     string
+	}{
+		{repeat: "", en: "", ru: ""},
+		{repeat: "m 31 2", en: "", ru: ""},
+		{repeat: "y", en: "every year", ru: "каждый год"},
+		{repeat: "d 1", en: "every day", ru: "каждый день"},
+		{repeat: "d 3", en: "every 3 days", ru: "каждые 3 дня"},
+		{repeat: "d 21", en: "every 21 days", ru: "каждый 21 день"},
+		{repeat: "w 1,5", en: "every Monday and Friday", ru: "по понедельникам и пятницам"},
+		{
+			repeat: "w 1,3 / 2",
+			en:     "every 2 weeks on Monday and Wednesday",
+			ru:     "раз в 2 недели по понедельникам и средам",
+		},
+		{repeat: "m 2tue", en: "on the 2nd Tuesday of every month", ru: "во 2-й вторник каждого месяца"},
+		{repeat: "m -1fri", en: "on the last Friday of every month", ru: "в последнюю пятницу каждого месяца"},
+		{
+			repeat: "m 1,15,-1 1,7",
+			en:     "on the 1st, 15th and last day of January and July",
+			ru:     "1-го, 15-го и последнего числа января и июля",
+		},
+		{
+			repeat: "m 1,-1sun",
+			en:     "on the 1st day and last Sunday of every month",
+			ru:     "1-го числа и в последнее воскресенье каждого месяца",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repeat, func(t *testing.T) {
+			t.Parallel()
+
+			if got := datevalidating.DescribeRepeat(tt.repeat, datevalidating.LanguageEN); got != tt.en {
+				t.Errorf("DescribeRepeat(%q, en) = %q, want %q", tt.repeat, got, tt.en)
+			}
+
+			if got := datevalidating.DescribeRepeat(tt.repeat, datevalidating.LanguageRU); got != tt.ru {
+				t.Errorf("DescribeRepeat(%q, ru) = %q, want %q", tt.repeat, got, tt.ru)
+			}
+		})
+	}
+}
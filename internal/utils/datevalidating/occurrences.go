@@ -0,0 +1,70 @@
+package datevalidating
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Occurrences возвращает даты задачи в интервале [from, to]: саму дату задачи
+// и, для повторяющихся задач, все следующие даты по правилу repeat.
+func Occurrences(date, repeat string, from, to time.Time) ([]time.Time, error) {
+	start, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse task.Date to time: error=%w", err)
+	}
+
+	var dates []time.Time
+	if !start.Before(from) && !start.After(to) {
+		dates = append(dates, start)
+	}
+
+	if repeat == "" || start.After(to) {
+		return dates, nil
+	}
+
+	rule, err := ParseRepeat(repeat)
+	if err != nil {
+		return nil, err
+	}
+
+	// Первое повторение после начала окна считается от даты задачи,
+	// чтобы сохранить шаг "d" и чётность недель "w ... / N".
+	next, err := rule.Next(from.AddDate(0, 0, -1), start)
+	for err == nil && !next.After(to) {
+		dates = append(dates, next)
+		next, err = rule.Next(next, next)
+	}
+
+	if err != nil && !errors.Is(err, ErrNoNextDate) {
+		return nil, err
+	}
+
+	return dates, nil
+}
+
+// SnoozedOccurrences — Occurrences для отложенной задачи: date — дата, на которую её
+// отложили, snoozedFrom — дата по расписанию. Отложенная дата заменяет одно вхождение,
+// следующие идут по расписанию от snoozedFrom начиная с ResumeDate.
+func SnoozedOccurrences(date, snoozedFrom, repeat string, from, to time.Time) ([]time.Time, error) {
+	dates, err := Occurrences(date, "", from, to)
+	if err != nil || repeat == "" {
+		return dates, err
+	}
+
+	resume, err := ResumeDate(date, snoozedFrom, repeat)
+	if errors.Is(err, ErrNoNextDate) {
+		return dates, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := Occurrences(resume, repeat, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(dates, series...), nil
+}
@@ -0,0 +1,441 @@
+package datevalidating
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+)
+
+type RuleKind byte
+
+const (
+	Yearly  RuleKind = 'y'
+	Daily   RuleKind = 'd'
+	Weekly  RuleKind = 'w'
+	Monthly RuleKind = 'm'
+)
+
+const (
+	dateLayout = "20060102"
+
+	maxDailyInterval   = 400
+	maxWeeklyInterval  = 52
+	searchHorizonYears = 50
+)
+
+var ErrNoNextDate = errors.New("next date not found within search horizon")
+
+var weekdayNames = map[string]time.Weekday{
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+	"sun": time.Sunday,
+}
+
+var maxDaysInMonth = [...]int{0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+type (
+	// MonthDay — элемент списка дней правила "m": число месяца (отрицательное считается
+	// с конца месяца) либо n-й день недели, например "2tue" или "-1fri".
+	MonthDay struct {
+		Day     int
+		Nth     int
+		Weekday time.Weekday
+	}
+
+	// Rule — разобранное правило повторения задачи.
+	Rule struct {
+		Kind      RuleKind
+		Interval  int
+		Weekdays  []time.Weekday
+		MonthDays []MonthDay
+		Months    []time.Month
+	}
+)
+
+type token struct {
+	text string
+	pos  int
+}
+
+type parser struct {
+	repeat string
+	tokens []token
+}
+
+// ParseRepeat разбирает строку повторения:
+//
+//	y
+//	d <1..400>
+//	w <1..7>[,...] [/ <недель>]
+//	m <день|-1|-2|Nwday>[,...] [<1..12>[,...]]
+func ParseRepeat(repeat string) (*Rule, error) {
+	p := &parser{repeat: repeat, tokens: tokenize(repeat)}
+	if len(p.tokens) == 0 {
+		return nil, p.errorf(1, "repeat is empty")
+	}
+
+	head := p.tokens[0]
+	if len(head.text) != 1 {
+		return nil, p.errorf(head.pos, "unknown rule %q", head.text)
+	}
+
+	var (
+		rule *Rule
+		err  error
+		used int
+	)
+
+	switch RuleKind(head.text[0]) {
+	case Yearly:
+		rule, used = &Rule{Kind: Yearly}, 1
+	case Daily:
+		rule, used, err = p.parseDaily()
+	case Weekly:
+		rule, used, err = p.parseWeekly()
+	case Monthly:
+		rule, used, err = p.parseMonthly()
+	default:
+		return nil, p.errorf(head.pos, "unknown rule %q", head.text)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if used < len(p.tokens) {
+		return nil, p.errorf(p.tokens[used].pos, "unexpected %q", p.tokens[used].text)
+	}
+
+	return rule, nil
+}
+
+func (p *parser) parseDaily() (*Rule, int, error) {
+	tok, err := p.expect(1, "number of days")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	days, err := p.number(tok.text, tok.pos, 1, maxDailyInterval)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &Rule{Kind: Daily, Interval: days}, 2, nil
+}
+
+func (p *parser) parseWeekly() (*Rule, int, error) {
+	tok, err := p.expect(1, "list of weekdays")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rule := &Rule{Kind: Weekly, Interval: 1}
+
+	err = p.list(tok, func(item string, pos int) error {
+		n, err := p.number(item, pos, 1, 7)
+		if err != nil {
+			return err
+		}
+
+		rule.Weekdays = append(rule.Weekdays, time.Weekday(n%7))
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(p.tokens) < 3 || p.tokens[2].text != "/" {
+		return rule, 2, nil
+	}
+
+	tok, err = p.expect(3, "interval in weeks")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rule.Interval, err = p.number(tok.text, tok.pos, 1, maxWeeklyInterval)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rule, 4, nil
+}
+
+func (p *parser) parseMonthly() (*Rule, int, error) {
+	daysTok, err := p.expect(1, "list of days")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rule := &Rule{Kind: Monthly}
+
+	err = p.list(daysTok, func(item string, pos int) error {
+		day, err := p.monthDay(item, pos)
+		if err != nil {
+			return err
+		}
+
+		rule.MonthDays = append(rule.MonthDays, day)
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	used := 2
+	if len(p.tokens) > 2 {
+		err = p.list(p.tokens[2], func(item string, pos int) error {
+			n, err := p.number(item, pos, 1, 12)
+			if err != nil {
+				return err
+			}
+
+			rule.Months = append(rule.Months, time.Month(n))
+
+			return nil
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		used = 3
+	}
+
+	if !rule.feasible() {
+		return nil, 0, p.errorf(daysTok.pos, "days %q never occur in the selected months", daysTok.text)
+	}
+
+	return rule, used, nil
+}
+
+func (p *parser) monthDay(item string, pos int) (MonthDay, error) {
+	if len(item) > 3 && unicode.IsLetter(rune(item[len(item)-1])) {
+		wd, ok := weekdayNames[strings.ToLower(item[len(item)-3:])]
+		if !ok {
+			return MonthDay{}, p.errorf(pos+len(item)-3, "unknown weekday %q", item[len(item)-3:])
+		}
+
+		n, err := strconv.Atoi(item[:len(item)-3])
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return MonthDay{}, p.errorf(pos, "weekday number must be 1..5 or -1..-5, got %q", item[:len(item)-3])
+		}
+
+		return MonthDay{Nth: n, Weekday: wd}, nil
+	}
+
+	day, err := p.number(item, pos, -2, 31)
+	if err != nil {
+		return MonthDay{}, err
+	}
+
+	if day == 0 {
+		return MonthDay{}, p.errorf(pos, "day must not be 0")
+	}
+
+	return MonthDay{Day: day}, nil
+}
+
+func (p *parser) expect(i int, what string) (token, error) {
+	if i >= len(p.tokens) {
+		return token{}, p.errorf(len(p.repeat)+1, "expected %s", what)
+	}
+
+	return p.tokens[i], nil
+}
+
+func (p *parser) list(tok token, item func(item string, pos int) error) error {
+	pos := tok.pos
+	for _, s := range strings.Split(tok.text, ",") {
+		if s == "" {
+			return p.errorf(pos, "empty list item")
+		}
+
+		if err := item(s, pos); err != nil {
+			return err
+		}
+
+		pos += len(s) + 1
+	}
+
+	return nil
+}
+
+func (p *parser) number(s string, pos, lo, hi int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, p.errorf(pos, "%q is not a number", s)
+	}
+
+	if n < lo || n > hi {
+		return 0, p.errorf(pos, "%d is out of range %d..%d", n, lo, hi)
+	}
+
+	return n, nil
+}
+
+func (p *parser) errorf(pos int, format string, args ...any) error {
+	return errorspkg.NewRepeatParseError(p.repeat, pos, fmt.Sprintf(format, args...))
+}
+
+func tokenize(s string) []token {
+	var tokens []token
+
+	start := -1
+	for i, r := range s {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			tokens = append(tokens, token{text: s[start:i], pos: start + 1})
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, token{text: s[start:], pos: start + 1})
+	}
+
+	return tokens
+}
+
+func (r *Rule) feasible() bool {
+	for _, d := range r.MonthDays {
+		for m := time.January; m <= time.December; m++ {
+			if len(r.Months) > 0 && !containsMonth(r.Months, m) {
+				continue
+			}
+
+			if d.Nth != 0 || d.Day < 0 || d.Day <= maxDaysInMonth[m] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Next возвращает первую дату правила, которая позже и date, и дня now.
+func (r *Rule) Next(now, date time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch r.Kind {
+	case Yearly:
+		next := date.AddDate(1, 0, 0)
+		for !next.After(today) {
+			next = next.AddDate(1, 0, 0)
+		}
+
+		return next, nil
+
+	case Daily:
+		next := date.AddDate(0, 0, r.Interval)
+		if !next.After(today) {
+			steps := daysBetween(next, today)/r.Interval + 1
+			next = next.AddDate(0, 0, steps*r.Interval)
+		}
+
+		return next, nil
+	}
+
+	cursor := date
+	if today.After(cursor) {
+		cursor = today
+	}
+
+	limit := cursor.AddDate(searchHorizonYears, 0, 0)
+	anchor := startOfWeek(date)
+
+	for d := cursor.AddDate(0, 0, 1); !d.After(limit); d = d.AddDate(0, 0, 1) {
+		if r.matches(d, anchor) {
+			return d, nil
+		}
+	}
+
+	return time.Time{}, ErrNoNextDate
+}
+
+func (r *Rule) matches(date, anchor time.Time) bool {
+	switch r.Kind {
+	case Weekly:
+		if (daysBetween(anchor, startOfWeek(date))/7)%r.Interval != 0 {
+			return false
+		}
+
+		for _, wd := range r.Weekdays {
+			if date.Weekday() == wd {
+				return true
+			}
+		}
+
+	case Monthly:
+		if len(r.Months) > 0 && !containsMonth(r.Months, date.Month()) {
+			return false
+		}
+
+		for _, d := range r.MonthDays {
+			if d.matches(date) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (d MonthDay) matches(date time.Time) bool {
+	last := daysInMonth(date)
+
+	if d.Nth == 0 {
+		if d.Day < 0 {
+			return date.Day() == last+d.Day+1
+		}
+
+		return date.Day() == d.Day
+	}
+
+	if date.Weekday() != d.Weekday {
+		return false
+	}
+
+	if d.Nth > 0 {
+		return (date.Day()-1)/7+1 == d.Nth
+	}
+
+	return (last-date.Day())/7+1 == -d.Nth
+}
+
+func containsMonth(months []time.Month, m time.Month) bool {
+	for _, month := range months {
+		if month == m {
+			return true
+		}
+	}
+
+	return false
+}
+
+// startOfWeek возвращает понедельник недели, в которую попадает date.
+func startOfWeek(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+
+	return date.AddDate(0, 0, -offset)
+}
+
+func daysInMonth(date time.Time) int {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func daysBetween(from, to time.Time) int {
+	return int((to.Unix() - from.Unix()) / (24 * 60 * 60))
+}
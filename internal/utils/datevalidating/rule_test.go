@@ -0,0 +1,104 @@
+package datevalidating
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+)
+
+var grammarExamples = []string{
+	"y",
+	"d 1",
+	"d 400",
+	"w 1,3,5",
+	"w 7",
+	"w 2,4 / 3",
+	"m 1",
+	"m -1,-2",
+	"m 31",
+	"m 29 2",
+	"m 1,15 3,6,9,12",
+	"m 2tue",
+	"m -1fri 1,7",
+	"m 5sun,-5mon",
+}
+
+func FuzzParseRepeat(f *testing.F) {
+	for _, repeat := range grammarExamples {
+		f.Add(repeat)
+	}
+
+	f.Add("")
+	f.Add("m 31 2")
+	f.Add("w 1 /")
+
+	f.Fuzz(func(t *testing.T, repeat string) {
+		rule, err := ParseRepeat(repeat)
+		if err != nil {
+			var perr errorspkg.RepeatParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("ParseRepeat(%q) error = %v, want RepeatParseError", repeat, err)
+			}
+
+			if perr.Position < 1 || perr.Position > len(repeat)+1 {
+				t.Fatalf("ParseRepeat(%q) position %d is outside 1..%d", repeat, perr.Position, len(repeat)+1)
+			}
+
+			return
+		}
+
+		if rule.Kind == Monthly && !rule.feasible() {
+			t.Fatalf("ParseRepeat(%q) accepted a rule that never occurs", repeat)
+		}
+	})
+}
+
+// FuzzRuleNext проверяет, что Next не зависает и возвращает дату правила позже
+// обеих входных дат либо ErrNoNextDate.
+func FuzzRuleNext(f *testing.F) {
+	for _, repeat := range grammarExamples {
+		f.Add(repeat, int32(0), int32(0))
+		f.Add(repeat, int32(-400), int32(30))
+		f.Add(repeat, int32(3000), int32(-3000))
+	}
+
+	base := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	f.Fuzz(func(t *testing.T, repeat string, dateOffset, nowOffset int32) {
+		rule, err := ParseRepeat(repeat)
+		if err != nil {
+			return
+		}
+
+		// Держим даты в пределах ±searchHorizonYears лет от base.
+		const span = searchHorizonYears * 366
+		date := base.AddDate(0, 0, int(dateOffset%span))
+		now := base.AddDate(0, 0, int(nowOffset%span)).Add(13 * time.Hour)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+		next, err := rule.Next(now, date)
+		if errors.Is(err, ErrNoNextDate) {
+			if rule.Kind == Yearly || rule.Kind == Daily {
+				t.Fatalf("Next(%q) from %s: %v", repeat, date.Format(dateLayout), err)
+			}
+
+			return
+		}
+
+		if err != nil {
+			t.Fatalf("Next(%q) from %s: unexpected error %v", repeat, date.Format(dateLayout), err)
+		}
+
+		if !next.After(date) || !next.After(today) {
+			t.Fatalf("Next(%q, now %s, date %s) = %s, want a date after both",
+				repeat, today.Format(dateLayout), date.Format(dateLayout), next.Format(dateLayout))
+		}
+
+		if (rule.Kind == Weekly || rule.Kind == Monthly) && !rule.matches(next, startOfWeek(date)) {
+			t.Fatalf("Next(%q) from %s = %s, which does not match the rule",
+				repeat, date.Format(dateLayout), next.Format(dateLayout))
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var funcs = map[string]any{
+	"date": formatDate,
+}
+
+var (
+	textTemplate = template.Must(
+		template.New("digest.txt.tmpl").Funcs(funcs).ParseFS(templates, "templates/digest.txt.tmpl"))
+	htmlTemplate = htmltemplate.Must(
+		htmltemplate.New("digest.html.tmpl").Funcs(funcs).ParseFS(templates, "templates/digest.html.tmpl"))
+)
+
+// Data — содержимое сводки за день Day (ГГГГММДД).
+type Data struct {
+	Day      string
+	Overdue  []models.Task
+	Today    []models.Task
+	Tomorrow []models.Task
+}
+
+func (d *Data) Title() string {
+	return "Tasks for " + formatDate(d.Day)
+}
+
+func (d *Data) Empty() bool {
+	return len(d.Overdue) == 0 && len(d.Today) == 0 && len(d.Tomorrow) == 0
+}
+
+// Render возвращает текстовую и HTML-версии сводки.
+func Render(d *Data) (text, html string, err error) {
+	var buf bytes.Buffer
+	if err = textTemplate.Execute(&buf, d); err != nil {
+		return "", "", fmt.Errorf("rendering text digest: %w", err)
+	}
+
+	text = buf.String()
+	buf.Reset()
+
+	if err = htmlTemplate.Execute(&buf, d); err != nil {
+		return "", "", fmt.Errorf("rendering html digest: %w", err)
+	}
+
+	return text, buf.String(), nil
+}
+
+func formatDate(date string) string {
+	t, err := time.Parse("20060102", date)
+	if err != nil {
+		return date
+	}
+
+	return t.Format("2006-01-02")
+}
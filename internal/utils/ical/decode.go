@@ -0,0 +1,372 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/utils/datevalidating"
+)
+
+var weekdayNumbers = map[string]int{"MO": 1, "TU": 2, "WE": 3, "TH": 4, "FR": 5, "SA": 6, "SU": 7}
+
+// uidNamespace — пространство имён для id задач, полученных из чужих UID.
+var uidNamespace = uuid.NewSHA1(uuid.NameSpaceDNS, []byte(uidDomain))
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+type component struct {
+	kind  string
+	props map[string]property
+}
+
+// Decode читает компоненты VEVENT и VTODO и переводит их в задачи. Правила RRULE,
+// которые не выражаются в грамматике повторений, попадают в список проблем,
+// а задача импортируется без повторения.
+func Decode(r io.Reader) ([]models.Task, []models.ImportProblem, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		tasks      []models.Task
+		problems   []models.ImportProblem
+		stack      []string
+		current    *component
+		components []component
+	)
+
+	for n, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			kind := strings.ToUpper(prop.value)
+			stack = append(stack, kind)
+
+			if kind == ComponentEvent || kind == ComponentTodo {
+				current = &component{kind: kind, props: make(map[string]property)}
+			}
+
+			continue
+
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1] != strings.ToUpper(prop.value) {
+				return nil, nil, fmt.Errorf("line %d: unexpected END:%s", n+1, prop.value)
+			}
+
+			stack = stack[:len(stack)-1]
+
+			if current != nil && current.kind == strings.ToUpper(prop.value) {
+				components = append(components, *current)
+				current = nil
+			}
+
+			continue
+		}
+
+		// Свойства вложенных компонентов (например, VALARM) пропускаются.
+		if current != nil && stack[len(stack)-1] == current.kind {
+			if _, ok := current.props[prop.name]; !ok {
+				current.props[prop.name] = prop
+			}
+		}
+	}
+
+	if len(stack) != 0 {
+		return nil, nil, fmt.Errorf("unterminated component %s", stack[len(stack)-1])
+	}
+
+	for _, c := range components {
+		task, problem := c.task()
+		tasks = append(tasks, task)
+
+		if problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+
+	return tasks, problems, nil
+}
+
+func (c *component) task() (models.Task, *models.ImportProblem) {
+	task := models.Task{
+		ID:      taskID(c.props["UID"].value),
+		Title:   unescapeText(c.props["SUMMARY"].value),
+		Comment: unescapeText(c.props["DESCRIPTION"].value),
+	}
+
+	start, ok := c.props["DTSTART"]
+	if !ok && c.kind == ComponentTodo {
+		start, ok = c.props["DUE"]
+	}
+
+	var date time.Time
+	if ok && len(start.value) >= 8 {
+		if t, err := time.Parse(dateLayout, start.value[:8]); err == nil {
+			date = t
+			task.Date = t.Format(dateLayout)
+		}
+	}
+
+	rrule, ok := c.props["RRULE"]
+	if !ok {
+		return task, nil
+	}
+
+	repeat, err := RepeatFromRRule(rrule.value, date)
+	if err != nil {
+		return task, &models.ImportProblem{
+			UID:    c.props["UID"].value,
+			Title:  task.Title,
+			Value:  rrule.value,
+			Reason: err.Error(),
+		}
+	}
+
+	task.Repeat = repeat
+
+	return task, nil
+}
+
+// taskID переводит UID в id задачи. UID из собственного экспорта ("<id>@todo-list")
+// дают исходный id, остальные — постоянный uuid, чтобы повторный импорт обновлял
+// те же задачи, а не создавал копии.
+func taskID(uid string) string {
+	if uid == "" {
+		return ""
+	}
+
+	if id, ok := strings.CutSuffix(uid, "@"+uidDomain); ok {
+		if parsed, err := uuid.Parse(id); err == nil {
+			return parsed.String()
+		}
+	}
+
+	return uuid.NewSHA1(uidNamespace, []byte(uid)).String()
+}
+
+// RepeatFromRRule переводит RRULE в грамматику повторений задач. Дата start нужна
+// для правил без BYDAY/BYMONTHDAY, которые повторяются в день начала.
+func RepeatFromRRule(rrule string, start time.Time) (string, error) {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rrule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		parts[strings.ToUpper(key)] = strings.ToUpper(value)
+	}
+
+	interval := 1
+	if v, ok := parts["INTERVAL"]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return "", fmt.Errorf("invalid INTERVAL %q", v)
+		}
+
+		interval = n
+	}
+
+	for _, key := range []string{"COUNT", "UNTIL", "BYSETPOS", "BYYEARDAY", "BYWEEKNO", "BYHOUR", "BYMINUTE", "BYSECOND"} {
+		if _, ok := parts[key]; ok {
+			return "", fmt.Errorf("%s is not supported", key)
+		}
+	}
+
+	var repeat string
+
+	switch parts["FREQ"] {
+	case "YEARLY":
+		if interval != 1 || parts["BYDAY"] != "" || parts["BYMONTHDAY"] != "" || parts["BYMONTH"] != "" {
+			return "", fmt.Errorf("only plain yearly rules are supported")
+		}
+
+		repeat = "y"
+
+	case "DAILY":
+		if parts["BYDAY"] != "" || parts["BYMONTHDAY"] != "" || parts["BYMONTH"] != "" {
+			return "", fmt.Errorf("daily rules with BY* parts are not supported")
+		}
+
+		repeat = fmt.Sprintf("d %d", interval)
+
+	case "WEEKLY":
+		if parts["BYMONTHDAY"] != "" || parts["BYMONTH"] != "" {
+			return "", fmt.Errorf("weekly rules with BYMONTHDAY or BYMONTH are not supported")
+		}
+
+		days, err := weeklyDays(parts["BYDAY"], start)
+		if err != nil {
+			return "", err
+		}
+
+		repeat = "w " + days
+		if interval > 1 {
+			repeat += fmt.Sprintf(" / %d", interval)
+		}
+
+	case "MONTHLY":
+		if interval != 1 {
+			return "", fmt.Errorf("monthly INTERVAL is not supported")
+		}
+
+		days, err := monthlyDays(parts["BYMONTHDAY"], parts["BYDAY"], start)
+		if err != nil {
+			return "", err
+		}
+
+		repeat = "m " + days
+		if parts["BYMONTH"] != "" {
+			repeat += " " + parts["BYMONTH"]
+		}
+
+	default:
+		return "", fmt.Errorf("FREQ %q is not supported", parts["FREQ"])
+	}
+
+	if err := datevalidating.CheckCorrectRepeat(repeat); err != nil {
+		return "", err
+	}
+
+	return repeat, nil
+}
+
+func weeklyDays(byDay string, start time.Time) (string, error) {
+	if byDay == "" {
+		if start.IsZero() {
+			return "", fmt.Errorf("weekly rule without BYDAY and DTSTART")
+		}
+
+		return strconv.Itoa((int(start.Weekday())+6)%7 + 1), nil
+	}
+
+	days := strings.Split(byDay, ",")
+	for i, d := range days {
+		n, ok := weekdayNumbers[d]
+		if !ok {
+			return "", fmt.Errorf("BYDAY %q is not supported in weekly rules", d)
+		}
+
+		days[i] = strconv.Itoa(n)
+	}
+
+	return strings.Join(days, ","), nil
+}
+
+func monthlyDays(byMonthDay, byDay string, start time.Time) (string, error) {
+	switch {
+	case byMonthDay != "" && byDay != "":
+		return "", fmt.Errorf("BYMONTHDAY combined with BYDAY is not supported")
+
+	case byMonthDay != "":
+		return byMonthDay, nil
+
+	case byDay != "":
+		days := strings.Split(byDay, ",")
+		for i, d := range days {
+			if len(d) < 3 {
+				return "", fmt.Errorf("BYDAY %q needs an ordinal in monthly rules", d)
+			}
+
+			if _, ok := weekdayNumbers[d[len(d)-2:]]; !ok {
+				return "", fmt.Errorf("unknown weekday in BYDAY %q", d)
+			}
+
+			days[i] = strings.TrimPrefix(d[:len(d)-2], "+") + strings.ToLower(weekdayName(d[len(d)-2:]))
+		}
+
+		return strings.Join(days, ","), nil
+
+	case !start.IsZero():
+		return strconv.Itoa(start.Day()), nil
+	}
+
+	return "", fmt.Errorf("monthly rule without BYMONTHDAY, BYDAY and DTSTART")
+}
+
+func weekdayName(code string) string {
+	return time.Weekday(weekdayNumbers[code] % 7).String()[:3]
+}
+
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading calendar: %w", err)
+	}
+
+	return lines, nil
+}
+
+func parseProperty(line string) (property, error) {
+	inQuotes, colon := false, -1
+
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+
+		if r == ':' && !inQuotes {
+			colon = i
+
+			break
+		}
+	}
+
+	if colon < 0 {
+		return property{}, fmt.Errorf("malformed content line %q", line)
+	}
+
+	head := strings.Split(line[:colon], ";")
+	prop := property{
+		name:   strings.ToUpper(head[0]),
+		params: make(map[string]string, len(head)-1),
+		value:  line[colon+1:],
+	}
+
+	for _, p := range head[1:] {
+		key, value, _ := strings.Cut(p, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+
+	return prop, nil
+}
+
+func unescapeText(s string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(s)
+}
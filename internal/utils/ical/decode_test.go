@@ -0,0 +1,140 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sater-151/todo-list/internal/utils/ical"
+)
+
+func TestRepeatFromRRule(t *testing.T) {
+	t.Parallel()
+
+	// Воскресенье, 15 февраля.
+	start := time.Date(2026, time.February, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rrule string
+		want  string
+	}{
+		{rrule: "FREQ=YEARLY", want: "y"},
+		{rrule: "FREQ=DAILY", want: "d 1"},
+		{rrule: "FREQ=DAILY;INTERVAL=3", want: "d 3"},
+		{rrule: "FREQ=WEEKLY", want: "w 7"},
+		{rrule: "FREQ=WEEKLY;BYDAY=MO,WE", want: "w 1,3"},
+		{rrule: "freq=weekly;interval=2;byday=fr", want: "w 5 / 2"},
+		{rrule: "FREQ=MONTHLY", want: "m 15"},
+		{rrule: "FREQ=MONTHLY;BYMONTHDAY=1,15,-1", want: "m 1,15,-1"},
+		{rrule: "FREQ=MONTHLY;BYDAY=2TU", want: "m 2tue"},
+		{rrule: "FREQ=MONTHLY;BYDAY=+1MO,-1FR;BYMONTH=1,7", want: "m 1mon,-1fri 1,7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rrule, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ical.RepeatFromRRule(tt.rrule, start)
+			if err != nil {
+				t.Fatalf("RepeatFromRRule(%q) error: %v", tt.rrule, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("RepeatFromRRule(%q) = %q, want %q", tt.rrule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepeatFromRRuleUnsupported(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.February, 15, 0, 0, 0, 0, time.UTC)
+
+	for _, rrule := range []string{
+		"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+		"FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=1",
+		"FREQ=WEEKLY;BYDAY=MO;BYMONTH=6,7,8",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYDAY=TU",
+		"FREQ=MONTHLY;BYMONTHDAY=31;BYMONTH=2",
+		"FREQ=DAILY;COUNT=10",
+		"FREQ=YEARLY;INTERVAL=2",
+		"FREQ=HOURLY",
+	} {
+		t.Run(rrule, func(t *testing.T) {
+			t.Parallel()
+
+			if got, err := ical.RepeatFromRRule(rrule, start); err == nil {
+				t.Errorf("RepeatFromRRule(%q) = %q, want an error", rrule, got)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	const ownID = "0192f0c4-7d3a-7b6e-9c1d-2f4a5b6c7d8e"
+
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:" + ownID + "@todo-list",
+		"DTSTART;VALUE=DATE:20260105",
+		"SUMMARY:Report",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:040000008200E00074C5B7101A82E008@example.com",
+		"DTSTART:20260130T090000Z",
+		"SUMMARY:Payroll",
+		"RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"DUE;VALUE=DATE:20260201",
+		"SUMMARY:Taxes\\, part 1",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	tasks, problems, err := ical.Decode(strings.NewReader(calendar))
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+
+	if len(tasks) != 3 {
+		t.Fatalf("Decode returned %d tasks, want 3", len(tasks))
+	}
+
+	if tasks[0].ID != ownID || tasks[0].Repeat != "w 1" || tasks[0].Date != "20260105" {
+		t.Errorf("own task = %+v, want id %s, repeat %q", tasks[0], ownID, "w 1")
+	}
+
+	if _, err = uuid.Parse(tasks[1].ID); err != nil {
+		t.Errorf("foreign UID gave id %q: %v", tasks[1].ID, err)
+	}
+
+	if tasks[1].Repeat != "" || tasks[1].Date != "20260130" {
+		t.Errorf("task with BYSETPOS = %+v, want no repeat", tasks[1])
+	}
+
+	if tasks[2].ID != "" || tasks[2].Title != "Taxes, part 1" || tasks[2].Date != "20260201" {
+		t.Errorf("task without UID = %+v", tasks[2])
+	}
+
+	if len(problems) != 1 || problems[0].UID != "040000008200E00074C5B7101A82E008@example.com" {
+		t.Fatalf("problems = %+v, want one for the BYSETPOS rule", problems)
+	}
+
+	// Повторный импорт того же календаря должен дать те же id.
+	again, _, err := ical.Decode(strings.NewReader(calendar))
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+
+	if again[1].ID != tasks[1].ID {
+		t.Errorf("foreign UID gave id %q, then %q", tasks[1].ID, again[1].ID)
+	}
+}
@@ -0,0 +1,225 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/utils/datevalidating"
+)
+
+const (
+	ComponentEvent = "VEVENT"
+	ComponentTodo  = "VTODO"
+
+	dateLayout  = "20060102"
+	stampLayout = "20060102T150405Z"
+	uidDomain   = "todo-list"
+	maxLineLen  = 75
+)
+
+var byDay = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// RRules переводит правило повторения в значения RRULE (RFC 5545).
+// Правило "m", в котором смешаны числа месяца и n-е дни недели, не выражается
+// одним RRULE (BYMONTHDAY и BYDAY пересекаются), поэтому возвращается два значения.
+func RRules(rule *datevalidating.Rule) []string {
+	switch rule.Kind {
+	case datevalidating.Yearly:
+		return []string{"FREQ=YEARLY"}
+
+	case datevalidating.Daily:
+		return []string{withInterval("FREQ=DAILY", rule.Interval)}
+
+	case datevalidating.Weekly:
+		days := make([]string, 0, len(rule.Weekdays))
+		for _, wd := range rule.Weekdays {
+			days = append(days, byDay[wd])
+		}
+
+		return []string{withInterval("FREQ=WEEKLY", rule.Interval) + ";WKST=MO;BYDAY=" + strings.Join(days, ",")}
+
+	case datevalidating.Monthly:
+		var monthDays, weekdays []string
+		for _, d := range rule.MonthDays {
+			if d.Nth == 0 {
+				monthDays = append(monthDays, strconv.Itoa(d.Day))
+			} else {
+				weekdays = append(weekdays, strconv.Itoa(d.Nth)+byDay[d.Weekday])
+			}
+		}
+
+		var byMonth string
+		if len(rule.Months) > 0 {
+			months := make([]string, 0, len(rule.Months))
+			for _, m := range rule.Months {
+				months = append(months, strconv.Itoa(int(m)))
+			}
+
+			byMonth = ";BYMONTH=" + strings.Join(months, ",")
+		}
+
+		var rrules []string
+		if len(monthDays) > 0 {
+			rrules = append(rrules, "FREQ=MONTHLY;BYMONTHDAY="+strings.Join(monthDays, ",")+byMonth)
+		}
+
+		if len(weekdays) > 0 {
+			rrules = append(rrules, "FREQ=MONTHLY;BYDAY="+strings.Join(weekdays, ",")+byMonth)
+		}
+
+		return rrules
+	}
+
+	return nil
+}
+
+func withInterval(freq string, interval int) string {
+	if interval <= 1 {
+		return freq
+	}
+
+	return fmt.Sprintf("%s;INTERVAL=%d", freq, interval)
+}
+
+// Encode пишет задачи в формате iCalendar как компоненты VEVENT или VTODO.
+func Encode(w io.Writer, tasks []models.Task, component string, now time.Time) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:-//" + uidDomain + "//scheduler//RU")
+	e.line("CALSCALE:GREGORIAN")
+
+	stamp := now.UTC().Format(stampLayout)
+	for i := range tasks {
+		if tasks[i].SnoozedFrom != "" && tasks[i].Repeat != "" {
+			e.snoozed(&tasks[i], component, stamp)
+
+			continue
+		}
+
+		e.task(&tasks[i], component, stamp)
+	}
+
+	e.line("END:VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+
+	return e.w.Flush()
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) task(task *models.Task, component, stamp string) {
+	date, err := time.Parse(dateLayout, task.Date)
+	if err != nil {
+		return
+	}
+
+	var rrules []string
+	if task.Repeat != "" {
+		if rule, err := datevalidating.ParseRepeat(task.Repeat); err == nil {
+			rrules = RRules(rule)
+		}
+	}
+
+	if len(rrules) == 0 {
+		rrules = []string{""}
+	}
+
+	for i, rrule := range rrules {
+		uid := task.ID
+		if len(rrules) > 1 {
+			uid += "-" + strconv.Itoa(i+1)
+		}
+
+		e.line("BEGIN:" + component)
+		e.line("UID:" + uid + "@" + uidDomain)
+		e.line("DTSTAMP:" + stamp)
+		e.line("DTSTART;VALUE=DATE:" + date.Format(dateLayout))
+
+		if component == ComponentTodo {
+			e.line("DUE;VALUE=DATE:" + date.AddDate(0, 0, 1).Format(dateLayout))
+		} else {
+			e.line("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format(dateLayout))
+		}
+
+		e.line("SUMMARY:" + escapeText(task.Title))
+
+		if task.Comment != "" {
+			e.line("DESCRIPTION:" + escapeText(task.Comment))
+		}
+
+		if rrule != "" {
+			e.line("RRULE:" + rrule)
+		}
+
+		e.line("END:" + component)
+	}
+}
+
+// snoozed пишет отложенную повторяющуюся задачу: отложенную дату отдельным событием
+// и серию, которая продолжается по расписанию от task.SnoozedFrom после неё.
+func (e *encoder) snoozed(task *models.Task, component, stamp string) {
+	single := *task
+	single.ID += "-snoozed"
+	single.Repeat = ""
+	e.task(&single, component, stamp)
+
+	resume, err := datevalidating.ResumeDate(task.Date, task.SnoozedFrom, task.Repeat)
+	if err != nil {
+		return
+	}
+
+	series := *task
+	series.Date = resume
+	e.task(&series, component, stamp)
+}
+
+// line пишет строку контента, складывая её по 75 октетов и не разрывая UTF-8 символы.
+func (e *encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+
+	limit := maxLineLen
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+
+		if _, e.err = e.w.WriteString(s[:cut] + "\r\n "); e.err != nil {
+			return
+		}
+
+		s = s[cut:]
+		limit = maxLineLen - 1
+	}
+
+	_, e.err = e.w.WriteString(s + "\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
@@ -0,0 +1,478 @@
+package quickadd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/utils/datevalidating"
+)
+
+const dateLayout = "20060102"
+
+var ErrEmptyTitle = errors.New("quick add: title is empty")
+
+var (
+	clockRe   = regexp.MustCompile(`^\d{1,2}:\d{2}$`)
+	ordinalRe = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th|-го|-е|-й|-ю|-ое|-ого)?$`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+	"sunday":    time.Sunday,
+
+	"понедельник": time.Monday,
+	"вторник":     time.Tuesday,
+	"среда":       time.Wednesday,
+	"среду":       time.Wednesday,
+	"четверг":     time.Thursday,
+	"пятница":     time.Friday,
+	"пятницу":     time.Friday,
+	"суббота":     time.Saturday,
+	"субботу":     time.Saturday,
+	"воскресенье": time.Sunday,
+}
+
+// weekdaysPlural — формы для повторений: "mondays", "по понедельникам".
+var weekdaysPlural = map[string]time.Weekday{
+	"mondays":    time.Monday,
+	"tuesdays":   time.Tuesday,
+	"wednesdays": time.Wednesday,
+	"thursdays":  time.Thursday,
+	"fridays":    time.Friday,
+	"saturdays":  time.Saturday,
+	"sundays":    time.Sunday,
+
+	"понедельникам": time.Monday,
+	"вторникам":     time.Tuesday,
+	"средам":        time.Wednesday,
+	"четвергам":     time.Thursday,
+	"пятницам":      time.Friday,
+	"субботам":      time.Saturday,
+	"воскресеньям":  time.Sunday,
+}
+
+var relativeDays = map[string]int{
+	"today":       0,
+	"tomorrow":    1,
+	"сегодня":     0,
+	"завтра":      1,
+	"послезавтра": 2,
+}
+
+var (
+	everyWords = map[string]bool{
+		"every": true, "каждый": true, "каждую": true, "каждое": true, "каждые": true, "каждого": true,
+	}
+	lastWords = map[string]bool{
+		"last": true, "последний": true, "последнего": true, "последнюю": true, "последнее": true,
+	}
+	separatorWords = map[string]bool{"and": true, "и": true}
+)
+
+type parser struct {
+	today time.Time
+	raw   []string
+	words []string
+	pos   int
+
+	title []string
+	notes []string
+
+	date    time.Time
+	hasDate bool
+
+	repeat    string
+	monthDays []string
+	monthly   bool
+}
+
+// Parse разбирает строку быстрого добавления задачи, например
+// "Pay rent every month on the 1st #finance tomorrow 9:00" или
+// "Планёрка по понедельникам и средам 10:00". Теги и время попадают в комментарий.
+func Parse(text string, now time.Time) (*models.Task, error) {
+	raw := strings.Fields(text)
+
+	p := &parser{
+		today: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		raw:   raw,
+		words: make([]string, len(raw)),
+	}
+
+	for i, w := range raw {
+		p.words[i] = strings.ToLower(strings.Trim(w, ",.;!?"))
+	}
+
+	for p.pos < len(p.words) {
+		if p.note() || p.dotDate() || p.relativeDate() || p.weekdayDate() || p.repeatRule() {
+			continue
+		}
+
+		p.title = append(p.title, p.raw[p.pos])
+		p.pos++
+	}
+
+	title := strings.Trim(strings.Join(p.title, " "), " ,.;")
+	if title == "" {
+		return nil, ErrEmptyTitle
+	}
+
+	task := &models.Task{
+		Title:   title,
+		Comment: strings.Join(p.notes, " "),
+	}
+
+	if p.hasDate {
+		task.Date = p.date.Format(dateLayout)
+	}
+
+	if p.monthly {
+		days := p.monthDays
+		if len(days) == 0 {
+			base := p.today
+			if p.hasDate {
+				base = p.date
+			}
+
+			days = []string{strconv.Itoa(base.Day())}
+		}
+
+		p.repeat = "m " + strings.Join(days, ",")
+	}
+
+	if p.repeat != "" {
+		if err := datevalidating.CheckCorrectRepeat(p.repeat); err != nil {
+			return nil, fmt.Errorf("quick add: %w", err)
+		}
+
+		task.Repeat = p.repeat
+	}
+
+	return task, nil
+}
+
+func (p *parser) word(offset int) string {
+	if p.pos+offset >= len(p.words) {
+		return ""
+	}
+
+	return p.words[p.pos+offset]
+}
+
+func (p *parser) note() bool {
+	w := p.raw[p.pos]
+	if (strings.HasPrefix(w, "#") && len(w) > 1) || isClock(p.word(0)) {
+		p.notes = append(p.notes, w)
+		p.pos++
+
+		return true
+	}
+
+	return false
+}
+
+// isClock проверяет, что слово — корректное время вида "9:00" или "18:30".
+func isClock(w string) bool {
+	if !clockRe.MatchString(w) {
+		return false
+	}
+
+	_, err := time.Parse("15:04", w)
+
+	return err == nil
+}
+
+func (p *parser) dotDate() bool {
+	skip := 0
+	if p.word(0) == "on" || p.word(0) == "в" {
+		skip = 1
+	}
+
+	date, ok := datevalidating.ParseDotDate(p.word(skip))
+	if !ok {
+		return false
+	}
+
+	p.date, _ = time.Parse(dateLayout, date)
+	p.hasDate = true
+	p.pos += skip + 1
+
+	return true
+}
+
+func (p *parser) relativeDate() bool {
+	if p.word(0) == "day" && p.word(1) == "after" && p.word(2) == "tomorrow" {
+		p.setDate(p.today.AddDate(0, 0, 2))
+		p.pos += 3
+
+		return true
+	}
+
+	days, ok := relativeDays[p.word(0)]
+	if !ok {
+		return false
+	}
+
+	p.setDate(p.today.AddDate(0, 0, days))
+	p.pos++
+
+	return true
+}
+
+// weekdayDate распознаёт "friday", "on friday", "next friday", "в пятницу".
+func (p *parser) weekdayDate() bool {
+	skip, strict := 0, false
+
+	switch p.word(0) {
+	case "on", "в", "во":
+		skip = 1
+	case "next", "следующий", "следующую", "следующее":
+		skip, strict = 1, true
+	}
+
+	wd, ok := weekdays[p.word(skip)]
+	if !ok {
+		return false
+	}
+
+	days := (int(wd) - int(p.today.Weekday()) + 7) % 7
+	if strict && days == 0 {
+		days = 7
+	}
+
+	p.setDate(p.today.AddDate(0, 0, days))
+	p.pos += skip + 1
+
+	return true
+}
+
+func (p *parser) setDate(date time.Time) {
+	p.date = date
+	p.hasDate = true
+}
+
+func (p *parser) repeatRule() bool {
+	switch p.word(0) {
+	case "daily", "ежедневно":
+		p.repeat = "d 1"
+		p.pos++
+
+		return true
+	case "weekly", "еженедельно":
+		p.repeat = "d 7"
+		p.pos++
+
+		return true
+	case "yearly", "annually", "ежегодно":
+		p.repeat = "y"
+		p.pos++
+
+		return true
+	case "monthly", "ежемесячно":
+		p.pos++
+		p.monthly = true
+		p.monthDaysSpec()
+
+		return true
+	case "по":
+		return p.weekdayList(1, 1)
+	}
+
+	if !everyWords[p.word(0)] {
+		return false
+	}
+
+	return p.every()
+}
+
+func (p *parser) every() bool {
+	next := p.word(1)
+
+	switch next {
+	case "day", "день":
+		p.repeat = "d 1"
+		p.pos += 2
+
+		return true
+	case "week", "неделю":
+		p.pos += 2
+		if p.word(0) == "on" && p.weekdayList(1, 1) {
+			return true
+		}
+
+		p.repeat = "d 7"
+
+		return true
+	case "month", "месяц":
+		p.pos += 2
+		p.monthly = true
+		p.monthDaysSpec()
+
+		return true
+	case "year", "год":
+		p.repeat = "y"
+		p.pos += 2
+
+		return true
+	case "other":
+		return p.interval(2, 2)
+	}
+
+	if n, err := strconv.Atoi(next); err == nil && n > 0 {
+		return p.interval(n, 2)
+	}
+
+	return p.weekdayList(1, 1)
+}
+
+// interval разбирает "N days", "N weeks [on monday]" после "every N" / "каждые N".
+func (p *parser) interval(n, unitOffset int) bool {
+	switch p.word(unitOffset) {
+	case "day", "days", "дня", "дней", "день":
+		p.repeat = fmt.Sprintf("d %d", n)
+		p.pos += unitOffset + 1
+
+		return true
+	case "week", "weeks", "неделю", "недели", "недель":
+		p.pos += unitOffset + 1
+		if (p.word(0) == "on" || p.word(0) == "по") && p.weekdayList(1, n) {
+			return true
+		}
+
+		p.repeat = fmt.Sprintf("d %d", n*7)
+
+		return true
+	}
+
+	return false
+}
+
+// weekdayList разбирает перечисление дней недели, начиная со смещения offset.
+func (p *parser) weekdayList(offset, interval int) bool {
+	var days []string
+
+	i, end := offset, offset
+	for {
+		wd, ok := weekdays[p.word(i)]
+		if !ok {
+			wd, ok = weekdaysPlural[p.word(i)]
+		}
+
+		if !ok {
+			break
+		}
+
+		n := int(wd)
+		if wd == time.Sunday {
+			n = 7
+		}
+
+		days = append(days, strconv.Itoa(n))
+		end = i + 1
+
+		if i, ok = p.listNext(end); !ok {
+			break
+		}
+	}
+
+	if len(days) == 0 {
+		return false
+	}
+
+	p.repeat = "w " + strings.Join(days, ",")
+	if interval > 1 {
+		p.repeat += fmt.Sprintf(" / %d", interval)
+	}
+
+	p.pos += end
+
+	return true
+}
+
+// monthDaysSpec разбирает дни месяца после "every month": "on the 1st and 15th",
+// "on the last day", "on the 2nd tuesday", "1-го и 15-го числа", "в последнюю пятницу".
+func (p *parser) monthDaysSpec() {
+	i := 0
+
+	switch p.word(i) {
+	case "on", "в", "во":
+		i++
+	}
+
+	if p.word(i) == "the" {
+		i++
+	}
+
+	end := 0
+	for {
+		item, used := p.monthDay(i)
+		if used == 0 {
+			break
+		}
+
+		p.monthDays = append(p.monthDays, item)
+		i += used
+
+		switch p.word(i) {
+		case "day", "день", "числа", "число":
+			i++
+		}
+
+		end = i
+
+		var ok bool
+		if i, ok = p.listNext(end); !ok {
+			break
+		}
+
+		if p.word(i) == "the" || p.word(i) == "в" || p.word(i) == "во" {
+			i++
+		}
+	}
+
+	p.pos += end
+}
+
+// listNext возвращает смещение следующего элемента перечисления после end:
+// элементы разделяются запятой в конце слова или словами "and" / "и".
+func (p *parser) listNext(end int) (int, bool) {
+	if strings.HasSuffix(p.raw[p.pos+end-1], ",") {
+		return end, true
+	}
+
+	if separatorWords[p.word(end)] {
+		return end + 1, true
+	}
+
+	return end, false
+}
+
+func (p *parser) monthDay(i int) (string, int) {
+	w := p.word(i)
+
+	n := 0
+	if lastWords[w] {
+		n = -1
+	} else if m := ordinalRe.FindStringSubmatch(w); m != nil {
+		n, _ = strconv.Atoi(m[1])
+	}
+
+	if n == 0 || n > 31 {
+		return "", 0
+	}
+
+	if wd, ok := weekdays[p.word(i+1)]; ok && n <= 5 {
+		return fmt.Sprintf("%d%s", n, strings.ToLower(wd.String()[:3])), 2
+	}
+
+	return strconv.Itoa(n), 1
+}
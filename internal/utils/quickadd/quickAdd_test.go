@@ -0,0 +1,83 @@
+package quickadd_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/utils/quickadd"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	// Понедельник.
+	now := time.Date(2026, time.October, 19, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		text string
+		want models.Task
+	}{
+		{
+			text: "Pay rent every month on the 1st #finance tomorrow 9:00",
+			want: models.Task{Title: "Pay rent", Date: "20261020", Repeat: "m 1", Comment: "#finance 9:00"},
+		},
+		{
+			text: "Планёрка по понедельникам и средам 10:00",
+			want: models.Task{Title: "Планёрка", Repeat: "w 1,3", Comment: "10:00"},
+		},
+		{
+			text: "Полить цветы каждые 3 дня",
+			want: models.Task{Title: "Полить цветы", Repeat: "d 3"},
+		},
+		{
+			text: "Купить хлеб завтра",
+			want: models.Task{Title: "Купить хлеб", Date: "20261020"},
+		},
+		{
+			text: "Сдать отчёт 05.11.2026",
+			want: models.Task{Title: "Сдать отчёт", Date: "20261105"},
+		},
+		{
+			text: "Gym every monday and friday",
+			want: models.Task{Title: "Gym", Repeat: "w 1,5"},
+		},
+		{
+			text: "Read every page",
+			want: models.Task{Title: "Read every page"},
+		},
+		{
+			text: "Call mom on 32.13.2026",
+			want: models.Task{Title: "Call mom on 32.13.2026"},
+		},
+		{
+			text: "Meeting 25:99",
+			want: models.Task{Title: "Meeting 25:99"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := quickadd.Parse(tt.text, now)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.text, err)
+			}
+
+			if *got != tt.want {
+				t.Errorf("Parse(%q) =\n %+v\nwant\n %+v", tt.text, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEmptyTitle(t *testing.T) {
+	t.Parallel()
+
+	_, err := quickadd.Parse("#work tomorrow 9:00", time.Now())
+	if !errors.Is(err, quickadd.ErrEmptyTitle) {
+		t.Fatalf("Parse() error = %v, want %v", err, quickadd.ErrEmptyTitle)
+	}
+}
@@ -1,6 +1,16 @@
 package selectconfig
 
-import "github.com/sater-151/todo-list/internal/models"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/pkg/errorspkg"
+	"github.com/sater-151/todo-list/internal/pkg/validate"
+)
+
+const dateLayout = "20060102"
 
 func Default() *models.SelectConfig {
 	return &models.SelectConfig{
@@ -9,3 +19,75 @@ func Default() *models.SelectConfig {
 		Table: "scheduler",
 	}
 }
+
+// FromFilter проверяет фильтр списка задач и переносит его в SelectConfig.
+// Относительный период (Period) разворачивается в даты относительно now.
+func FromFilter(f *models.TaskFilter, now time.Time) (*models.SelectConfig, error) {
+	if err := validate.Struct(f); err != nil {
+		return nil, fmt.Errorf("%w: %w", errorspkg.ErrBadRequest, err)
+	}
+
+	if f.Period != "" && (f.From != "" || f.To != "") {
+		return nil, fmt.Errorf("%w: period [%s] cannot be combined with from/to", errorspkg.ErrBadRequest, f.Period)
+	}
+
+	if f.From != "" && f.To != "" && f.From > f.To {
+		return nil, fmt.Errorf("%w: from [%s] is after to [%s]", errorspkg.ErrBadRequest, f.From, f.To)
+	}
+
+	selectConfig := Default()
+	selectConfig.Search = f.Search
+	selectConfig.DateFrom = f.From
+	selectConfig.DateTo = f.To
+	selectConfig.Repeating = f.Repeating
+	selectConfig.HasComment = f.HasComment
+
+	for _, tag := range f.Tags {
+		selectConfig.Tags = append(selectConfig.Tags, strings.TrimPrefix(tag, "#"))
+	}
+
+	if f.Period != "" {
+		selectConfig.DateFrom, selectConfig.DateTo = periodBounds(f.Period, now)
+	}
+
+	if f.Overdue {
+		selectConfig.DateBefore = now.Format(dateLayout)
+	}
+
+	if f.Sort != "" {
+		selectConfig.Sort = f.Sort
+	}
+
+	selectConfig.TypeSort = strings.ToUpper(f.Order)
+
+	return selectConfig, nil
+}
+
+func periodBounds(period string, now time.Time) (string, string) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+
+	var from, to time.Time
+
+	switch period {
+	case "today":
+		from, to = today, today
+	case "tomorrow":
+		from, to = today.AddDate(0, 0, 1), today.AddDate(0, 0, 1)
+	case "today_and_overdue":
+		return "", today.Format(dateLayout)
+	case "this_week":
+		from, to = monday, monday.AddDate(0, 0, 6)
+	case "next_week":
+		from, to = monday.AddDate(0, 0, 7), monday.AddDate(0, 0, 13)
+	case "this_month":
+		from = today.AddDate(0, 0, 1-today.Day())
+		to = from.AddDate(0, 1, -1)
+	case "next_7_days":
+		from, to = today, today.AddDate(0, 0, 6)
+	default:
+		return "", ""
+	}
+
+	return from.Format(dateLayout), to.Format(dateLayout)
+}
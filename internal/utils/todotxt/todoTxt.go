@@ -0,0 +1,444 @@
+package todotxt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sater-151/todo-list/internal/models"
+	"github.com/sater-151/todo-list/internal/utils/datevalidating"
+)
+
+// Задача переводится в строку todo.txt так:
+//
+//	(A) Title +project @context id:<uuid> due:2026-01-05 rec:+1w note:pri%3AA%20%23project%20%40context
+//
+// Полей приоритета, проектов и контекстов у задачи нет, поэтому они хранятся
+// в комментарии: "pri:A", теги "#project" и слова "@context". Для других программ
+// они выводятся отдельными токенами, а комментарий целиком уходит в note:; при
+// разборе строки с note: комментарий берётся только из него. Правила, которые не
+// выражаются через rec:, пишутся как repeat:<правило>.
+//
+// Слова названия, которые иначе разбирались бы как разметка (+x, @x, key:value,
+// а в начале строки ещё x, приоритет и дата), пишутся с обратной косой чертой
+// в начале. Название с лишними пробелами дополнительно пишется в title:.
+const (
+	dateLayout    = "20060102"
+	todoTxtLayout = "2006-01-02"
+
+	keyID      = "id"
+	keyTitle   = "title"
+	keyDue     = "due"
+	keyRec     = "rec"
+	keyRepeat  = "repeat"
+	keyNote    = "note"
+	keySnoozed = "snoozed"
+	keyPri     = "pri"
+
+	escape = `\`
+)
+
+var (
+	priorityRe = regexp.MustCompile(`^\([A-Z]\)$`)
+	dateRe     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	recRe      = regexp.MustCompile(`^\+?(\d+)([dwmyb])$`)
+)
+
+// Encoder пишет задачи в формате todo.txt, по одной на строку.
+type Encoder struct {
+	w *bufio.Writer
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+func (e *Encoder) Encode(task *models.Task) error {
+	if _, err := e.w.WriteString(Format(task) + "\n"); err != nil {
+		return fmt.Errorf("writing todo.txt: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Encoder) Close() error {
+	return e.w.Flush()
+}
+
+// Format переводит задачу в строку todo.txt. ParseLine восстанавливает из неё
+// задачу без потерь.
+func Format(task *models.Task) string {
+	var (
+		priority string
+		tokens   []string
+	)
+
+	for _, w := range strings.Fields(task.Comment) {
+		switch {
+		case priority == "" && isPriority(w):
+			priority = w[len(keyPri)+1:]
+		case len(w) > 1 && w[0] == '#':
+			tokens = append(tokens, "+"+w[1:])
+		case len(w) > 1 && w[0] == '@':
+			tokens = append(tokens, w)
+		}
+	}
+
+	var parts []string
+	if priority != "" {
+		parts = append(parts, "("+priority+")")
+	}
+
+	words := strings.Fields(task.Title)
+	for i, w := range words {
+		if isMarkup(w, i == 0) {
+			w = escape + w
+		}
+
+		parts = append(parts, w)
+	}
+
+	parts = append(parts, tokens...)
+
+	if strings.Join(words, " ") != task.Title {
+		parts = append(parts, keyTitle+":"+url.PathEscape(task.Title))
+	}
+
+	if task.ID != "" {
+		parts = append(parts, keyID+":"+task.ID)
+	}
+
+	date, err := time.Parse(dateLayout, task.Date)
+	if err == nil {
+		parts = append(parts, keyDue+":"+date.Format(todoTxtLayout))
+	}
+
+	if task.Repeat != "" {
+		if rec, ok := recFromRepeat(task.Repeat, date); err == nil && ok {
+			parts = append(parts, keyRec+":"+rec)
+		} else {
+			parts = append(parts, keyRepeat+":"+url.PathEscape(task.Repeat))
+		}
+	}
+
+	if snoozed, err := time.Parse(dateLayout, task.SnoozedFrom); err == nil {
+		parts = append(parts, keySnoozed+":"+snoozed.Format(todoTxtLayout))
+	}
+
+	if task.Comment != "" {
+		parts = append(parts, keyNote+":"+url.PathEscape(task.Comment))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// isMarkup сообщает, разобрал бы ParseLine слово названия w как разметку. first —
+// слово стоит в начале строки, где x, приоритет и дата тоже имеют особый смысл.
+func isMarkup(w string, first bool) bool {
+	if strings.HasPrefix(w, escape) {
+		return true
+	}
+
+	if len(w) > 1 && (w[0] == '+' || w[0] == '@') {
+		return true
+	}
+
+	if key, value, ok := strings.Cut(w, ":"); ok && key != "" && value != "" {
+		return true
+	}
+
+	return first && (w == "x" || priorityRe.MatchString(w) || dateRe.MatchString(w))
+}
+
+// Decode читает задачи из todo.txt. Выполненные задачи ("x ...") и строки
+// с неподдерживаемым rec: пропускаются и попадают в список проблем.
+func Decode(r io.Reader) ([]models.Task, []models.ImportProblem, error) {
+	var (
+		tasks    []models.Task
+		problems []models.ImportProblem
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		task, err := ParseLine(line)
+		if err != nil {
+			problems = append(problems, models.ImportProblem{
+				Title:  task.Title,
+				Value:  line,
+				Reason: err.Error(),
+			})
+
+			continue
+		}
+
+		tasks = append(tasks, *task)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("reading todo.txt: %w", err)
+	}
+
+	return tasks, problems, nil
+}
+
+// ParseLine разбирает одну строку todo.txt. Задача возвращается и вместе
+// с ошибкой, чтобы в отчёте об импорте было видно её название.
+func ParseLine(line string) (*models.Task, error) {
+	words := strings.Fields(line)
+	task := &models.Task{}
+
+	if len(words) > 0 && words[0] == "x" {
+		task.Title = strings.Join(words[1:], " ")
+
+		return task, fmt.Errorf("completed task is skipped")
+	}
+
+	var (
+		priority, rec     string
+		note, exactTitle  *string
+		title, tags, ctxs []string
+	)
+
+	if len(words) > 0 && priorityRe.MatchString(words[0]) {
+		priority = words[0][1:2]
+		words = words[1:]
+	}
+
+	// Дата создания после приоритета в модели задачи не хранится.
+	if len(words) > 0 && dateRe.MatchString(words[0]) {
+		words = words[1:]
+	}
+
+	for _, w := range words {
+		if literal, ok := strings.CutPrefix(w, escape); ok {
+			title = append(title, literal)
+
+			continue
+		}
+
+		key, value, isKV := strings.Cut(w, ":")
+		if isKV && value != "" {
+			switch key {
+			case keyID:
+				task.ID = value
+
+				continue
+			case keyTitle:
+				text, err := url.PathUnescape(value)
+				if err != nil {
+					return task, fmt.Errorf("invalid title %q: %w", value, err)
+				}
+
+				exactTitle = &text
+
+				continue
+			case keySnoozed:
+				date, err := time.Parse(todoTxtLayout, value)
+				if err != nil {
+					return task, fmt.Errorf("invalid snoozed date %q", value)
+				}
+
+				task.SnoozedFrom = date.Format(dateLayout)
+
+				continue
+			case keyDue:
+				date, err := time.Parse(todoTxtLayout, value)
+				if err != nil {
+					return task, fmt.Errorf("invalid due date %q", value)
+				}
+
+				task.Date = date.Format(dateLayout)
+
+				continue
+			case keyRec:
+				rec = value
+
+				continue
+			case keyRepeat:
+				repeat, err := url.PathUnescape(value)
+				if err != nil {
+					return task, fmt.Errorf("invalid repeat %q: %w", value, err)
+				}
+
+				task.Repeat = repeat
+
+				continue
+			case keyNote:
+				text, err := url.PathUnescape(value)
+				if err != nil {
+					return task, fmt.Errorf("invalid note %q: %w", value, err)
+				}
+
+				note = &text
+
+				continue
+			}
+		}
+
+		switch {
+		case len(w) > 1 && w[0] == '+':
+			tags = append(tags, "#"+w[1:])
+		case len(w) > 1 && w[0] == '@':
+			ctxs = append(ctxs, w)
+		default:
+			title = append(title, w)
+		}
+	}
+
+	task.Title = strings.Join(title, " ")
+	if exactTitle != nil {
+		task.Title = *exactTitle
+	}
+
+	if note != nil {
+		task.Comment = *note
+	} else {
+		var comment []string
+		if priority != "" {
+			comment = append(comment, keyPri+":"+priority)
+		}
+
+		comment = append(comment, tags...)
+		comment = append(comment, ctxs...)
+		task.Comment = strings.Join(comment, " ")
+	}
+
+	if rec != "" {
+		repeat, err := repeatFromRec(rec, task.Date)
+		if err != nil {
+			return task, err
+		}
+
+		task.Repeat = repeat
+	}
+
+	return task, nil
+}
+
+// repeatFromRec переводит расширение rec: (N дней, недель, месяцев, лет или
+// рабочих дней) в правило повторения. Повторение всегда считается от даты задачи,
+// поэтому строгая форма "+N" и обычная "N" не различаются.
+func repeatFromRec(rec, date string) (string, error) {
+	m := recRe.FindStringSubmatch(rec)
+	if m == nil {
+		return "", fmt.Errorf("unsupported rec %q", rec)
+	}
+
+	n, _ := strconv.Atoi(m[1])
+	if n == 0 {
+		return "", fmt.Errorf("unsupported rec %q", rec)
+	}
+
+	base := time.Now()
+	if t, err := time.Parse(dateLayout, date); err == nil {
+		base = t
+	}
+
+	var repeat string
+
+	switch m[2] {
+	case "d":
+		repeat = fmt.Sprintf("d %d", n)
+	case "w":
+		repeat = fmt.Sprintf("w %d", isoWeekday(base))
+		if n > 1 {
+			repeat += fmt.Sprintf(" / %d", n)
+		}
+	case "m":
+		if n != 1 {
+			return "", fmt.Errorf("unsupported rec %q: monthly interval must be 1", rec)
+		}
+
+		repeat = fmt.Sprintf("m %d", base.Day())
+	case "y":
+		if n != 1 {
+			return "", fmt.Errorf("unsupported rec %q: yearly interval must be 1", rec)
+		}
+
+		repeat = "y"
+	case "b":
+		if n != 1 {
+			return "", fmt.Errorf("unsupported rec %q: business day interval must be 1", rec)
+		}
+
+		repeat = "w 1,2,3,4,5"
+	}
+
+	if err := datevalidating.CheckCorrectRepeat(repeat); err != nil {
+		return "", fmt.Errorf("unsupported rec %q: %w", rec, err)
+	}
+
+	return repeat, nil
+}
+
+// recFromRepeat — обратное к repeatFromRec преобразование. Правило выражается
+// через rec: только если повторный импорт даст то же самое правило.
+func recFromRepeat(repeat string, date time.Time) (string, bool) {
+	rule, err := datevalidating.ParseRepeat(repeat)
+	if err != nil {
+		return "", false
+	}
+
+	var rec string
+
+	switch rule.Kind {
+	case datevalidating.Daily:
+		rec = fmt.Sprintf("+%dd", rule.Interval)
+	case datevalidating.Weekly:
+		switch {
+		case len(rule.Weekdays) == 1 && isoWeekday(date) == isoNumber(rule.Weekdays[0]):
+			rec = fmt.Sprintf("+%dw", rule.Interval)
+		case rule.Interval == 1 && len(rule.Weekdays) == 5 && isWorkweek(rule.Weekdays):
+			rec = "+1b"
+		}
+	case datevalidating.Monthly:
+		if len(rule.MonthDays) == 1 && len(rule.Months) == 0 &&
+			rule.MonthDays[0].Nth == 0 && rule.MonthDays[0].Day == date.Day() {
+			rec = "+1m"
+		}
+	case datevalidating.Yearly:
+		rec = "+1y"
+	}
+
+	if rec == "" {
+		return "", false
+	}
+
+	back, err := repeatFromRec(rec, date.Format(dateLayout))
+
+	return rec, err == nil && back == repeat
+}
+
+func isPriority(w string) bool {
+	return len(w) == len(keyPri)+2 && strings.HasPrefix(w, keyPri+":") && w[len(w)-1] >= 'A' && w[len(w)-1] <= 'Z'
+}
+
+func isWorkweek(days []time.Weekday) bool {
+	for i, wd := range days {
+		if isoNumber(wd) != i+1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isoWeekday(t time.Time) int {
+	return isoNumber(t.Weekday())
+}
+
+func isoNumber(wd time.Weekday) int {
+	return (int(wd)+6)%7 + 1
+}
@@ -0,0 +1,207 @@
+package todotxt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sater-151/todo-list/internal/models"
+)
+
+func TestFormatParseLineRoundTrip(t *testing.T) {
+	const id = "0192f0c4-7d3a-7b6e-9c1d-2f4a5b6c7d8e"
+
+	tests := []struct {
+		name string
+		task models.Task
+	}{
+		{
+			name: "plain",
+			task: models.Task{ID: id, Date: "20260105", Title: "Buy milk"},
+		},
+		{
+			name: "without id and date",
+			task: models.Task{Title: "Buy milk"},
+		},
+		{
+			name: "comment kept verbatim",
+			task: models.Task{
+				ID:      id,
+				Date:    "20260105",
+				Title:   "Report",
+				Comment: "pri:A #work  @office\nsecond line: 100% done, see https://example.com/a?b=c",
+			},
+		},
+		{
+			name: "comment with only tags",
+			task: models.Task{Date: "20260105", Title: "Report", Comment: "#work @office"},
+		},
+		{
+			name: "title with markup words",
+			task: models.Task{Date: "20260105", Title: "Call +Ann @home about due:tomorrow and rec:1d"},
+		},
+		{
+			name: "title starting with x",
+			task: models.Task{Date: "20260105", Title: "x marks the spot"},
+		},
+		{
+			name: "title starting with priority",
+			task: models.Task{Date: "20260105", Title: "(B) is not a priority"},
+		},
+		{
+			name: "title starting with date",
+			task: models.Task{Date: "20260105", Title: "2026-01-01 retrospective"},
+		},
+		{
+			name: "title with backslashes",
+			task: models.Task{Date: "20260105", Title: `\x and \+y C:\path`},
+		},
+		{
+			name: "title with extra spaces",
+			task: models.Task{Date: "20260105", Title: "  two  spaces\tand tab "},
+		},
+		{
+			name: "repeat as rec",
+			task: models.Task{Date: "20260105", Title: "Standup", Repeat: "w 1"},
+		},
+		{
+			name: "repeat without rec",
+			task: models.Task{Date: "20260105", Title: "Rent", Repeat: "m -1fri 1,7"},
+		},
+		{
+			name: "snoozed",
+			task: models.Task{Date: "20260107", Title: "Gym", Repeat: "d 2", SnoozedFrom: "20260105"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := Format(&tt.task)
+
+			got, err := ParseLine(line)
+			if err != nil {
+				t.Fatalf("ParseLine(%q) error: %v", line, err)
+			}
+
+			if *got != tt.task {
+				t.Errorf("round trip through %q:\n got  %#v\n want %#v", line, *got, tt.task)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	task := models.Task{
+		ID:      "0192f0c4-7d3a-7b6e-9c1d-2f4a5b6c7d8e",
+		Date:    "20260105",
+		Title:   "Call +Ann",
+		Comment: "pri:A #work @office",
+		Repeat:  "w 1",
+	}
+
+	want := `(A) Call \+Ann +work @office id:0192f0c4-7d3a-7b6e-9c1d-2f4a5b6c7d8e due:2026-01-05 rec:+1w ` +
+		`note:pri:A%20%23work%20@office`
+
+	if got := Format(&task); got != want {
+		t.Errorf("Format() =\n %q\nwant\n %q", got, want)
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    models.Task
+		wantErr bool
+	}{
+		{
+			name: "tags from foreign file",
+			line: "(A) 2026-01-01 Call Ann +work @phone due:2026-01-05",
+			want: models.Task{Date: "20260105", Title: "Call Ann", Comment: "pri:A #work @phone"},
+		},
+		{
+			name: "note overrides tags",
+			line: "Call Ann +work note:just%20a%20note",
+			want: models.Task{Title: "Call Ann", Comment: "just a note"},
+		},
+		{
+			name: "rec",
+			line: "Water plants due:2026-01-05 rec:+3d",
+			want: models.Task{Date: "20260105", Title: "Water plants", Repeat: "d 3"},
+		},
+		{
+			name:    "completed",
+			line:    "x 2026-01-05 Done already",
+			want:    models.Task{Title: "2026-01-05 Done already"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported rec",
+			line:    "Pay due:2026-01-05 rec:2m",
+			want:    models.Task{Date: "20260105", Title: "Pay"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid due",
+			line:    "Pay due:2026-13-05",
+			want:    models.Task{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLine(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLine(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			}
+
+			if *got != tt.want {
+				t.Errorf("ParseLine(%q) =\n %#v\nwant\n %#v", tt.line, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	tasks := []models.Task{
+		{ID: "0192f0c4-7d3a-7b6e-9c1d-2f4a5b6c7d8e", Date: "20260105", Title: "First", Comment: "a\nb"},
+		{Date: "20260106", Title: "x second", Repeat: "y"},
+	}
+
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	for i := range tasks {
+		if err := enc.Encode(&tasks[i]); err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+	}
+
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if n := strings.Count(buf.String(), "\n"); n != len(tasks) {
+		t.Fatalf("encoded %d lines, want %d:\n%s", n, len(tasks), buf.String())
+	}
+
+	got, problems, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if len(problems) != 0 {
+		t.Fatalf("Decode problems: %+v", problems)
+	}
+
+	if len(got) != len(tasks) {
+		t.Fatalf("decoded %d tasks, want %d", len(got), len(tasks))
+	}
+
+	for i := range tasks {
+		if got[i] != tasks[i] {
+			t.Errorf("task %d:\n got  %#v\n want %#v", i, got[i], tasks[i])
+		}
+	}
+}
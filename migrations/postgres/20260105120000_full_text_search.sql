@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE scheduler
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(comment, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(comment, '')), 'B')
+    ) STORED;
+
+CREATE INDEX scheduler_search_vector_idx ON scheduler USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX scheduler_search_vector_idx;
+
+ALTER TABLE scheduler DROP COLUMN search_vector;
+-- +goose StatementEnd
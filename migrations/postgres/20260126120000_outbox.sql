@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE outbox (
+    id BIGSERIAL NOT NULL,
+    event_type TEXT NOT NULL,
+    task_uuid UUID,
+    payload JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT outbox_pk PRIMARY KEY (id)
+);
+
+-- Релей доставляет события как минимум один раз, повторная постановка
+-- доставки вебхука для того же события должна быть безопасной.
+CREATE UNIQUE INDEX webhook_deliveries_event_idx ON webhook_deliveries (webhook_uuid, event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX webhook_deliveries_event_idx;
+DROP TABLE outbox;
+-- +goose StatementEnd
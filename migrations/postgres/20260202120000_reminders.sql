@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE reminders (
+    uuid UUID NOT NULL,
+    task_uuid UUID NOT NULL,
+    days_before INTEGER NOT NULL DEFAULT 0,
+    at_time TIME NOT NULL,
+    email TEXT NOT NULL,
+    -- Дата задачи, к которой относятся status и attempts. У повторяющейся задачи
+    -- после переноса на следующую дату напоминание срабатывает снова.
+    occurrence TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT reminders_pk PRIMARY KEY (uuid),
+    CONSTRAINT reminders_task_fk FOREIGN KEY (task_uuid) REFERENCES scheduler (uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX reminders_task_idx ON reminders (task_uuid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE reminders;
+-- +goose StatementEnd
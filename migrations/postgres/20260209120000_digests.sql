@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Одна строка на получателя и день: гарантирует, что сводка уходит один раз,
+-- даже если сервис перезапущен или запущен в нескольких экземплярах.
+CREATE TABLE digests (
+    recipient TEXT NOT NULL,
+    day TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    lease_until TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at TIMESTAMPTZ,
+
+    CONSTRAINT digests_pk PRIMARY KEY (recipient, day)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE digests;
+-- +goose StatementEnd
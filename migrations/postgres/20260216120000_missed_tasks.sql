@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Пропущенные даты повторяющихся задач, которые фоновая задача перенесла вперёд.
+CREATE TABLE missed_tasks (
+    id BIGSERIAL NOT NULL,
+    task_uuid UUID NOT NULL,
+    date TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT missed_tasks_pk PRIMARY KEY (id),
+    CONSTRAINT missed_tasks_task_fk FOREIGN KEY (task_uuid) REFERENCES scheduler (uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX missed_tasks_task_idx ON missed_tasks (task_uuid, date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE missed_tasks;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Дата задачи до первого откладывания. По ней TaskDone считает следующую дату
+-- повторяющейся задачи, чтобы откладывание не сдвигало расписание.
+ALTER TABLE scheduler ADD COLUMN snoozed_from BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE scheduler DROP COLUMN snoozed_from;
+-- +goose StatementEnd
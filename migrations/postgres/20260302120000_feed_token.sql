@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Секрет ссылки на календарный фид. Строка одна: новый секрет заменяет прежний
+-- и отзывает все выданные ссылки.
+CREATE TABLE feed_tokens (
+    id BOOLEAN NOT NULL DEFAULT TRUE,
+    token TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT feed_tokens_pk PRIMARY KEY (id),
+    CONSTRAINT feed_tokens_single CHECK (id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE feed_tokens;
+-- +goose StatementEnd
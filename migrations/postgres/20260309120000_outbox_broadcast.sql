@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Обработанные релеем события хранятся ещё некоторое время: по уведомлению их читают
+-- все экземпляры сервиса, чтобы разослать подписчикам своей шины событий.
+ALTER TABLE outbox ADD COLUMN processed_at TIMESTAMPTZ;
+
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE processed_at IS NULL;
+CREATE INDEX outbox_processed_idx ON outbox (processed_at);
+
+CREATE FUNCTION outbox_notify() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox_events', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_notify AFTER INSERT ON outbox
+    FOR EACH ROW EXECUTE FUNCTION outbox_notify();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER outbox_notify ON outbox;
+DROP FUNCTION outbox_notify();
+DROP INDEX outbox_processed_idx;
+DROP INDEX outbox_pending_idx;
+DELETE FROM outbox WHERE processed_at IS NOT NULL;
+ALTER TABLE outbox DROP COLUMN processed_at;
+-- +goose StatementEnd